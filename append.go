@@ -0,0 +1,375 @@
+package bytestream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// The Append* functions encode a value exactly like the matching Write* method but append it to dst and return the extended slice, the
+// way strconv.Append* does. Appending into a buffer with enough capacity doesn't allocate, so hot loops can encode into a pooled buffer
+// or a stack array without a Writer.
+//
+// The functions whose Write* method can fail for reasons other than the underlying stream (a value that doesn't fit, a length that
+// can't be encoded, a compressor error) also return an error, dst is returned unchanged in that case and the *Error's Offset is len(dst).
+// WriteBoolean has no Append counterpart since packing booleans needs to remember the last byte, and WriteBytes is just append.
+
+func AppendBool(dst []byte, data bool, count int8) []byte {
+	if !data {
+		return append(dst, 0x00)
+	}
+	return append(dst, byte(count))
+}
+
+func AppendInt8(dst []byte, data int8) []byte {
+	return append(dst, byte(data))
+}
+
+func AppendUInt8(dst []byte, data uint8) []byte {
+	return append(dst, data)
+}
+
+func AppendInt16(dst []byte, data int16, endianness Endianness) []byte {
+	return appendUInt(dst, uint64(data), Int16Size, endianness)
+}
+
+func AppendUInt16(dst []byte, data uint16, endianness Endianness) []byte {
+	return appendUInt(dst, uint64(data), Int16Size, endianness)
+}
+
+// AppendInt24 fails with ErrOverflow if data doesn't fit in 3 bytes.
+func AppendInt24(dst []byte, data int32, endianness Endianness) ([]byte, error) {
+	return appendIntSize("AppendInt24", 0, dst, int64(data), Int24Size, endianness)
+}
+
+// AppendUInt24 fails with ErrOverflow if data doesn't fit in 3 bytes.
+func AppendUInt24(dst []byte, data uint32, endianness Endianness) ([]byte, error) {
+	return appendUIntSize("AppendUInt24", 0, dst, uint64(data), Int24Size, endianness)
+}
+
+func AppendInt32(dst []byte, data int32, endianness Endianness) []byte {
+	return appendUInt(dst, uint64(data), Int32Size, endianness)
+}
+
+func AppendUInt32(dst []byte, data uint32, endianness Endianness) []byte {
+	return appendUInt(dst, uint64(data), Int32Size, endianness)
+}
+
+func AppendInt64(dst []byte, data int64, endianness Endianness) []byte {
+	return appendUInt(dst, uint64(data), Int64Size, endianness)
+}
+
+func AppendUInt64(dst []byte, data uint64, endianness Endianness) []byte {
+	return appendUInt(dst, data, Int64Size, endianness)
+}
+
+func AppendFloat32(dst []byte, data float32, endianness Endianness) []byte {
+	return appendUInt(dst, uint64(math.Float32bits(data)), Float32Size, endianness)
+}
+
+func AppendFloat64(dst []byte, data float64, endianness Endianness) []byte {
+	return appendUInt(dst, math.Float64bits(data), Float64Size, endianness)
+}
+
+// AppendUIntSize appends data as an unsigned integer that is size bytes wide, see WriteUIntSize.
+func AppendUIntSize(dst []byte, data int64, size uint8, endianness Endianness) ([]byte, error) {
+	return appendUIntSize("AppendUIntSize", 0, dst, uint64(data), size, endianness)
+}
+
+// AppendIntSize is the signed counterpart of AppendUIntSize.
+func AppendIntSize(dst []byte, data int64, size uint8, endianness Endianness) ([]byte, error) {
+	return appendIntSize("AppendIntSize", 0, dst, data, size, endianness)
+}
+
+// AppendInt appends value as an integer that is size bytes wide, signed or unsigned as sign says, see WriteInt.
+func AppendInt(dst []byte, value int64, size uint8, sign Sign, endianness Endianness) ([]byte, error) {
+	return appendIntSign("AppendInt", 0, dst, value, size, sign, endianness)
+}
+
+func AppendLong(dst []byte, data int64, endianness Endianness) []byte {
+	if Is64Bit {
+		return appendUInt(dst, uint64(data), Int64Size, endianness)
+	}
+	return appendUInt(dst, uint64(uint32(data)), Int32Size, endianness)
+}
+
+func AppendUnsignedLong(dst []byte, data uint64, endianness Endianness) []byte {
+	if Is64Bit {
+		return appendUInt(dst, data, Int64Size, endianness)
+	}
+	return appendUInt(dst, uint64(uint32(data)), Int32Size, endianness)
+}
+
+func AppendLongLong(dst []byte, data int64, endianness Endianness) []byte {
+	return appendUInt(dst, uint64(data), Int64Size, endianness)
+}
+
+func AppendUnsignedLongLong(dst []byte, data uint64, endianness Endianness) []byte {
+	return appendUInt(dst, data, Int64Size, endianness)
+}
+
+func AppendLogicLong(dst []byte, data LogicLong, endianness Endianness) []byte {
+	dst = appendUInt(dst, uint64(uint32(data.High)), Int32Size, endianness)
+	return appendUInt(dst, uint64(uint32(data.Low)), Int32Size, endianness)
+}
+
+func AppendVarInt(dst []byte, data int64) []byte {
+	return appendVarInt(dst, data)
+}
+
+func AppendUVarInt(dst []byte, data uint64) []byte {
+	return appendUVarInt(dst, data)
+}
+
+// AppendRRSInt32 appends data as the game's VInt, see ReadRRSInt32.
+func AppendRRSInt32(dst []byte, data int32) []byte {
+	return appendRRSInt32(dst, data)
+}
+
+// AppendRRSLong appends a LogicLong as two VInts, high first.
+func AppendRRSLong(dst []byte, data LogicLong) []byte {
+	return appendRRSInt32(appendRRSInt32(dst, data.High), data.Low)
+}
+
+// AppendString appends data with an int32 length prefix, like WriteString.
+func AppendString(dst []byte, data string) ([]byte, error) {
+	return appendPrefixedString("AppendString", 0, dst, data, CountInt32)
+}
+
+// AppendNullableString is like AppendString but appends a null string (a length of -1) for nil.
+func AppendNullableString(dst []byte, data *string) ([]byte, error) {
+	if data == nil {
+		return appendLength("AppendNullableString", 0, dst, CountInt32, 0, true)
+	}
+	return appendPrefixedString("AppendNullableString", 0, dst, *data, CountInt32)
+}
+
+// AppendNullableBytes appends data with an int32 length prefix, nil is appended as a length of -1.
+func AppendNullableBytes(dst []byte, data []byte) ([]byte, error) {
+	return appendPrefixedBytes("AppendNullableBytes", 0, dst, data, CountInt32)
+}
+
+// AppendStringSize appends data with a big endian length prefix that is bytesize bytes wide, see WriteStringSize.
+func AppendStringSize(dst []byte, data string, bytesize int8) ([]byte, error) {
+	if bytesize < 1 || bytesize > Int64Size {
+		return dst, encodeFail("AppendStringSize", 0, dst, ErrInvalidLength, Int64Size, int64(bytesize), nil)
+	}
+	p := LengthPrefix{Encoding: LengthFixed, Size: uint8(bytesize), Endianness: BigEndian, Sign: Signed}
+	return appendPrefixedString("AppendStringSize", 0, dst, data, p)
+}
+
+// AppendLength appends length encoded as p, -1 appends p.Null and fails with ErrInvalidLength if p isn't Nullable.
+func AppendLength(dst []byte, length int, p LengthPrefix) ([]byte, error) {
+	if length == -1 {
+		if !p.Nullable {
+			return dst, encodeFail("AppendLength", 0, dst, ErrInvalidLength, 0, -1, nil)
+		}
+		return appendLength("AppendLength", 0, dst, p, 0, true)
+	}
+	if length < 0 {
+		return dst, encodeFail("AppendLength", 0, dst, ErrInvalidLength, 0, int64(length), nil)
+	}
+	return appendLength("AppendLength", 0, dst, p, length, false)
+}
+
+// AppendPrefixedString appends data with a length prefix encoded as p.
+func AppendPrefixedString(dst []byte, data string, p LengthPrefix) ([]byte, error) {
+	return appendPrefixedString("AppendPrefixedString", 0, dst, data, p)
+}
+
+// AppendPrefixedBytes appends data with a length prefix encoded as p, nil is appended as p.Null when p is Nullable.
+func AppendPrefixedBytes(dst []byte, data []byte, p LengthPrefix) ([]byte, error) {
+	return appendPrefixedBytes("AppendPrefixedBytes", 0, dst, data, p)
+}
+
+// AppendCompressedString appends data zlib compressed, like WriteCompressedString. The compressed data is built in a temporary
+// buffer first, so unlike the other Append functions this one always allocates.
+func AppendCompressedString(dst []byte, data string) ([]byte, error) {
+	return appendCompressed("AppendCompressedString", 0, dst, []byte(data), Zlib)
+}
+
+// AppendNullableCompressedString is like AppendCompressedString but appends a null string (a compressed size of -1) for nil.
+func AppendNullableCompressedString(dst []byte, data *string) ([]byte, error) {
+	if data == nil {
+		return AppendInt32(dst, -1, BigEndian), nil
+	}
+	return appendCompressed("AppendNullableCompressedString", 0, dst, []byte(*data), Zlib)
+}
+
+// AppendCompressedStringWith is like AppendCompressedString but compresses with c instead of zlib.
+func AppendCompressedStringWith(dst []byte, data string, c Compressor) ([]byte, error) {
+	return appendCompressed("AppendCompressedStringWith", 0, dst, []byte(data), c)
+}
+
+// AppendCompressedBytes appends data the way AppendCompressedString appends strings, nil is appended as null (a compressed size of -1).
+func AppendCompressedBytes(dst []byte, data []byte) ([]byte, error) {
+	return AppendCompressedBytesWith(dst, data, Zlib)
+}
+
+// AppendCompressedBytesWith is like AppendCompressedBytes but compresses with c instead of zlib.
+func AppendCompressedBytesWith(dst []byte, data []byte, c Compressor) ([]byte, error) {
+	if data == nil {
+		return AppendInt32(dst, -1, BigEndian), nil
+	}
+	return appendCompressed("AppendCompressedBytesWith", 0, dst, data, c)
+}
+
+// The unexported append helpers are shared with Writer, which appends into a stack array and writes that. base is the stream offset
+// of dst[0], it's only used for the Offset of errors.
+
+func encodeFail(op string, base int64, dst []byte, kind error, expected, actual int64, err error) *Error {
+	return &Error{Op: op, Offset: base + int64(len(dst)), Expected: expected, Actual: actual, Kind: kind, Err: err}
+}
+
+// appendUInt appends the low size bytes of data, the caller has made sure size is valid.
+func appendUInt(dst []byte, data uint64, size uint8, endianness Endianness) []byte {
+	n := len(dst)
+	dst = append(dst, make([]byte, size)...)
+	_bytes := dst[n:]
+	switch {
+	case size == Int16Size && endianness == BigEndian:
+		binary.BigEndian.PutUint16(_bytes, uint16(data))
+	case size == Int16Size:
+		binary.LittleEndian.PutUint16(_bytes, uint16(data))
+	case size == Int32Size && endianness == BigEndian:
+		binary.BigEndian.PutUint32(_bytes, uint32(data))
+	case size == Int32Size:
+		binary.LittleEndian.PutUint32(_bytes, uint32(data))
+	case size == Int64Size && endianness == BigEndian:
+		binary.BigEndian.PutUint64(_bytes, data)
+	case size == Int64Size:
+		binary.LittleEndian.PutUint64(_bytes, data)
+	case endianness == BigEndian:
+		for i := int(size) - 1; i >= 0; i-- {
+			_bytes[i] = byte(data)
+			data >>= 8
+		}
+	default:
+		for i := 0; i < int(size); i++ {
+			_bytes[i] = byte(data)
+			data >>= 8
+		}
+	}
+	return dst
+}
+
+func appendUIntSize(op string, base int64, dst []byte, data uint64, size uint8, endianness Endianness) ([]byte, error) {
+	if size < 1 || size > Int64Size {
+		return dst, encodeFail(op, base, dst, ErrInvalidLength, Int64Size, int64(size), nil)
+	}
+	if size < Int64Size && data>>(8*uint(size)) != 0 {
+		return dst, encodeFail(op, base, dst, ErrOverflow, int64(size), int64(unsignedSize(data)), nil)
+	}
+	return appendUInt(dst, data, size, endianness), nil
+}
+
+func appendIntSize(op string, base int64, dst []byte, data int64, size uint8, endianness Endianness) ([]byte, error) {
+	if size < 1 || size > Int64Size {
+		return dst, encodeFail(op, base, dst, ErrInvalidLength, Int64Size, int64(size), nil)
+	}
+	if size < Int64Size {
+		bits := 8 * uint(size)
+		if data < -1<<(bits-1) || data > 1<<(bits-1)-1 {
+			return dst, encodeFail(op, base, dst, ErrOverflow, int64(size), int64(signedSize(data)), nil)
+		}
+	}
+	// appendUInt only looks at the low size bytes, so the two's complement comes out right
+	return appendUInt(dst, uint64(data), size, endianness), nil
+}
+
+func appendIntSign(op string, base int64, dst []byte, data int64, size uint8, sign Sign, endianness Endianness) ([]byte, error) {
+	if sign == Signed {
+		return appendIntSize(op, base, dst, data, size, endianness)
+	}
+	if size < 1 || size > Int64Size {
+		return dst, encodeFail(op, base, dst, ErrInvalidLength, Int64Size, int64(size), nil)
+	}
+	if data < 0 {
+		return dst, encodeFail(op, base, dst, ErrOverflow, int64(size), Int64Size, nil)
+	}
+	return appendUIntSize(op, base, dst, uint64(data), size, endianness)
+}
+
+func appendVarInt(dst []byte, data int64) []byte {
+	ux := uint64(data) << 1
+	if data < 0 {
+		ux = ^ux
+	}
+	return appendUVarInt(dst, ux)
+}
+
+func appendUVarInt(dst []byte, data uint64) []byte {
+	for data >= 0x80 {
+		dst = append(dst, byte(data)|0x80)
+		data >>= 7
+	}
+	return append(dst, byte(data))
+}
+
+func appendRRSInt32(dst []byte, data int32) []byte {
+	// Negative numbers are stored as their complement with bit 6 of the first byte set
+	value := uint32(data)
+	_byte := byte(0x00)
+	if data < 0 {
+		value = ^value
+		_byte = 0x40
+	}
+	_byte |= byte(value & 0x3F)
+	value >>= 6
+	for value != 0 {
+		dst = append(dst, _byte|0x80)
+		_byte = byte(value & 0x7F)
+		value >>= 7
+	}
+	return append(dst, _byte)
+}
+
+func appendPrefixedString(op string, base int64, dst []byte, data string, p LengthPrefix) ([]byte, error) {
+	out, err := appendLength(op, base, dst, p, len(data), false)
+	if err != nil {
+		return dst, err
+	}
+	return append(out, data...), nil
+}
+
+func appendPrefixedBytes(op string, base int64, dst []byte, data []byte, p LengthPrefix) ([]byte, error) {
+	out, err := appendLength(op, base, dst, p, len(data), data == nil)
+	if err != nil {
+		return dst, err
+	}
+	return append(out, data...), nil
+}
+
+// compress returns data compressed with c.
+func compress(op string, base int64, data []byte, c Compressor) ([]byte, error) {
+	intermediateBuffer := bytes.NewBuffer([]byte{})
+	compressor, err := c.NewWriter(intermediateBuffer)
+	if err != nil {
+		return nil, &Error{Op: op, Offset: base, Err: err}
+	}
+	n, err := compressor.Write(data)
+	if err != nil {
+		return nil, &Error{Op: op, Offset: base, Expected: int64(len(data)), Actual: int64(n), Err: err}
+	}
+	if err = compressor.Close(); err != nil {
+		return nil, &Error{Op: op, Offset: base, Err: err}
+	}
+	return intermediateBuffer.Bytes(), nil
+}
+
+// appendCompressed appends the compressed size (big endian), the decompressed size (little endian) and data compressed with c.
+func appendCompressed(op string, base int64, dst []byte, data []byte, c Compressor) ([]byte, error) {
+	compressed, err := compress(op, base+int64(len(dst)), data, c)
+	if err != nil {
+		return dst, err
+	}
+	out, err := appendIntSize(op, base, dst, int64(len(compressed)), Int32Size, BigEndian)
+	if err != nil {
+		return dst, err
+	}
+	out, err = appendIntSize(op, base, out, int64(len(data)), Int32Size, LittleEndian)
+	if err != nil {
+		return dst, err
+	}
+	return append(out, compressed...), nil
+}
@@ -0,0 +1,124 @@
+package bytestream
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestAppend(t *testing.T) {
+	hello := "hello"
+	prefix := []byte{0xAA}
+	// Every case appends to prefix and writes the same value with a Writer, the bytes after prefix have to match
+	tests := []struct {
+		name   string
+		append func(dst []byte) ([]byte, error)
+		write  func(w *Writer) error
+	}{
+		{"Bool", func(dst []byte) ([]byte, error) { return AppendBool(dst, true, 3), nil }, func(w *Writer) error { return w.WriteBool(true, 3) }},
+		{"Int8", func(dst []byte) ([]byte, error) { return AppendInt8(dst, -2), nil }, func(w *Writer) error { return w.WriteInt8(-2) }},
+		{"UInt8", func(dst []byte) ([]byte, error) { return AppendUInt8(dst, 0xFE), nil }, func(w *Writer) error { return w.WriteUInt8(0xFE) }},
+		{"Int16", func(dst []byte) ([]byte, error) { return AppendInt16(dst, -300, BigEndian), nil }, func(w *Writer) error { return w.WriteInt16(-300, BigEndian) }},
+		{"UInt16", func(dst []byte) ([]byte, error) { return AppendUInt16(dst, 0xBEEF, LittleEndian), nil }, func(w *Writer) error { return w.WriteUInt16(0xBEEF, LittleEndian) }},
+		{"Int24", func(dst []byte) ([]byte, error) { return AppendInt24(dst, -123456, BigEndian) }, func(w *Writer) error { return w.WriteInt24(-123456, BigEndian) }},
+		{"UInt24", func(dst []byte) ([]byte, error) { return AppendUInt24(dst, 0xABCDEF, LittleEndian) }, func(w *Writer) error { return w.WriteUInt24(0xABCDEF, LittleEndian) }},
+		{"Int32", func(dst []byte) ([]byte, error) { return AppendInt32(dst, -123456789, BigEndian), nil }, func(w *Writer) error { return w.WriteInt32(-123456789, BigEndian) }},
+		{"UInt32", func(dst []byte) ([]byte, error) { return AppendUInt32(dst, 0xDEADBEEF, LittleEndian), nil }, func(w *Writer) error { return w.WriteUInt32(0xDEADBEEF, LittleEndian) }},
+		{"Int64", func(dst []byte) ([]byte, error) { return AppendInt64(dst, -1234567890123, BigEndian), nil }, func(w *Writer) error { return w.WriteInt64(-1234567890123, BigEndian) }},
+		{"UInt64", func(dst []byte) ([]byte, error) { return AppendUInt64(dst, 1<<63+5, LittleEndian), nil }, func(w *Writer) error { return w.WriteUInt64(1<<63+5, LittleEndian) }},
+		{"Float32", func(dst []byte) ([]byte, error) { return AppendFloat32(dst, -1.5, BigEndian), nil }, func(w *Writer) error { return w.WriteFloat32(-1.5, BigEndian) }},
+		{"Float64", func(dst []byte) ([]byte, error) { return AppendFloat64(dst, 3.25, LittleEndian), nil }, func(w *Writer) error { return w.WriteFloat64(3.25, LittleEndian) }},
+		{"UIntSize", func(dst []byte) ([]byte, error) { return AppendUIntSize(dst, 0x0102030405, 5, BigEndian) }, func(w *Writer) error { return w.WriteUIntSize(0x0102030405, 5, BigEndian) }},
+		{"IntSize", func(dst []byte) ([]byte, error) { return AppendIntSize(dst, -2, 6, LittleEndian) }, func(w *Writer) error { return w.WriteIntSize(-2, 6, LittleEndian) }},
+		{"Int", func(dst []byte) ([]byte, error) { return AppendInt(dst, 0xFFFF, 2, Unsigned, LittleEndian) }, func(w *Writer) error { return w.WriteInt(0xFFFF, 2, Unsigned, LittleEndian) }},
+		{"Long", func(dst []byte) ([]byte, error) { return AppendLong(dst, -7, BigEndian), nil }, func(w *Writer) error { return w.WriteLong(-7, BigEndian) }},
+		{"UnsignedLong", func(dst []byte) ([]byte, error) { return AppendUnsignedLong(dst, 7, LittleEndian), nil }, func(w *Writer) error { return w.WriteUnsignedLong(7, LittleEndian) }},
+		{"LongLong", func(dst []byte) ([]byte, error) { return AppendLongLong(dst, -7, BigEndian), nil }, func(w *Writer) error { return w.WriteLongLong(-7, BigEndian) }},
+		{"UnsignedLongLong", func(dst []byte) ([]byte, error) { return AppendUnsignedLongLong(dst, 7, BigEndian), nil }, func(w *Writer) error { return w.WriteUnsignedLongLong(7, BigEndian) }},
+		{"LogicLong", func(dst []byte) ([]byte, error) {
+			return AppendLogicLong(dst, LogicLong{High: 1, Low: -2}, BigEndian), nil
+		}, func(w *Writer) error { return w.WriteLogicLong(LogicLong{High: 1, Low: -2}, BigEndian) }},
+		{"VarInt", func(dst []byte) ([]byte, error) { return AppendVarInt(dst, -300), nil }, func(w *Writer) error { return w.WriteVarInt(-300) }},
+		{"UVarInt", func(dst []byte) ([]byte, error) { return AppendUVarInt(dst, 1<<40), nil }, func(w *Writer) error { return w.WriteUVarInt(1 << 40) }},
+		{"RRSInt32", func(dst []byte) ([]byte, error) { return AppendRRSInt32(dst, -1<<31), nil }, func(w *Writer) error { return w.WriteRRSInt32(-1 << 31) }},
+		{"RRSLong", func(dst []byte) ([]byte, error) { return AppendRRSLong(dst, LogicLong{High: 64, Low: -65}), nil }, func(w *Writer) error { return w.WriteRRSLong(LogicLong{High: 64, Low: -65}) }},
+		{"String", func(dst []byte) ([]byte, error) { return AppendString(dst, "hello") }, func(w *Writer) error { return w.WriteString("hello") }},
+		{"NullableString", func(dst []byte) ([]byte, error) { return AppendNullableString(dst, &hello) }, func(w *Writer) error { return w.WriteNullableString(&hello) }},
+		{"NullableString (nil)", func(dst []byte) ([]byte, error) { return AppendNullableString(dst, nil) }, func(w *Writer) error { return w.WriteNullableString(nil) }},
+		{"NullableBytes (nil)", func(dst []byte) ([]byte, error) { return AppendNullableBytes(dst, nil) }, func(w *Writer) error { return w.WriteNullableBytes(nil) }},
+		{"StringSize", func(dst []byte) ([]byte, error) { return AppendStringSize(dst, "hi", 2) }, func(w *Writer) error { return w.WriteStringSize("hi", 2) }},
+		{"Length", func(dst []byte) ([]byte, error) { return AppendLength(dst, -1, CountVInt) }, func(w *Writer) error { return w.WriteLength(-1, CountVInt) }},
+		{"PrefixedString", func(dst []byte) ([]byte, error) { return AppendPrefixedString(dst, "hi", CountUVarInt) }, func(w *Writer) error { return w.WritePrefixedString("hi", CountUVarInt) }},
+		{"PrefixedBytes", func(dst []byte) ([]byte, error) { return AppendPrefixedBytes(dst, []byte{0x01}, CountByte) }, func(w *Writer) error { return w.WritePrefixedBytes([]byte{0x01}, CountByte) }},
+		{"CompressedString", func(dst []byte) ([]byte, error) { return AppendCompressedString(dst, "hello hello") }, func(w *Writer) error { return w.WriteCompressedString("hello hello") }},
+		{"NullableCompressedString (nil)", func(dst []byte) ([]byte, error) { return AppendNullableCompressedString(dst, nil) }, func(w *Writer) error { return w.WriteNullableCompressedString(nil) }},
+		{"CompressedStringWith", func(dst []byte) ([]byte, error) { return AppendCompressedStringWith(dst, "hello", Gzip) }, func(w *Writer) error { return w.WriteCompressedStringWith("hello", Gzip) }},
+		{"CompressedBytes (nil)", func(dst []byte) ([]byte, error) { return AppendCompressedBytes(dst, nil) }, func(w *Writer) error { return w.WriteCompressedBytes(nil) }},
+		{"CompressedBytesWith", func(dst []byte) ([]byte, error) { return AppendCompressedBytesWith(dst, []byte{0x01, 0x02}, Deflate) }, func(w *Writer) error { return w.WriteCompressedBytesWith([]byte{0x01, 0x02}, Deflate) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter()
+			if err := tt.write(w); err != nil {
+				t.Fatalf("Writer error = %v", err)
+			}
+			got, err := tt.append(append([]byte{}, prefix...))
+			if err != nil {
+				t.Fatalf("Append error = %v", err)
+			}
+			if !bytes.Equal(got[:len(prefix)], prefix) || !bytes.Equal(got[len(prefix):], w.Buffer.Bytes()) {
+				t.Errorf("Append = %v, want %v followed by %v", got, prefix, w.Buffer.Bytes())
+			}
+		})
+	}
+}
+
+func TestAppend_Errors(t *testing.T) {
+	dst := []byte{0x01, 0x02}
+	tests := []struct {
+		name   string
+		append func(dst []byte) ([]byte, error)
+		kind   error
+		op     string
+	}{
+		{name: "int24 overflow", append: func(dst []byte) ([]byte, error) { return AppendInt24(dst, 1<<23, BigEndian) }, kind: ErrOverflow, op: "AppendInt24"},
+		{name: "uint24 overflow", append: func(dst []byte) ([]byte, error) { return AppendUInt24(dst, 1<<24, BigEndian) }, kind: ErrOverflow, op: "AppendUInt24"},
+		{name: "unsigned negative", append: func(dst []byte) ([]byte, error) { return AppendInt(dst, -1, 2, Unsigned, BigEndian) }, kind: ErrOverflow, op: "AppendInt"},
+		{name: "invalid size", append: func(dst []byte) ([]byte, error) { return AppendIntSize(dst, 1, 9, BigEndian) }, kind: ErrInvalidLength, op: "AppendIntSize"},
+		{name: "string too long for its size", append: func(dst []byte) ([]byte, error) { return AppendStringSize(dst, string(make([]byte, 128)), 1) }, kind: ErrOverflow, op: "AppendStringSize"},
+		{name: "length can't be null", append: func(dst []byte) ([]byte, error) { return AppendLength(dst, -1, CountUVarInt) }, kind: ErrInvalidLength, op: "AppendLength"},
+		{name: "bytes too long for a byte prefix", append: func(dst []byte) ([]byte, error) { return AppendPrefixedBytes(dst, make([]byte, 256), CountByte) }, kind: ErrOverflow, op: "AppendPrefixedBytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.append(dst)
+			var e *Error
+			if !errors.Is(err, tt.kind) || !errors.As(err, &e) {
+				t.Fatalf("error = %v, want %v", err, tt.kind)
+			}
+			if e.Op != tt.op || e.Offset != int64(len(dst)) {
+				t.Errorf("error = %+v, want op %v offset %v", e, tt.op, len(dst))
+			}
+			if !bytes.Equal(got, dst) {
+				t.Errorf("Append = %v, want dst unchanged %v", got, dst)
+			}
+		})
+	}
+}
+
+func TestAppend_Allocs(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst := AppendInt16(buf[:0], 1, BigEndian)
+		dst, _ = AppendInt24(dst, 1, LittleEndian)
+		dst = AppendInt32(dst, 1, BigEndian)
+		dst = AppendInt64(dst, 1, BigEndian)
+		dst = AppendVarInt(dst, -300)
+		dst = AppendRRSInt32(dst, -300)
+		dst = AppendLogicLong(dst, LogicLong{High: 1, Low: 2}, BigEndian)
+		AppendString(dst, "hello")
+	})
+	if allocs != 0 {
+		t.Errorf("appending into a big enough buffer allocates %v times, want 0", allocs)
+	}
+}
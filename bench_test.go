@@ -0,0 +1,276 @@
+package bytestream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+)
+
+// The legacy* functions are how the primitives used to be read and written (a make per read, binary.Write per write), they're only
+// kept here so the benchmarks have something to compare against.
+
+func legacyReadInt16(buf *bytes.Buffer, endianness Endianness) (int16, error) {
+	_bytes := make([]byte, 2)
+	n, err := buf.Read(_bytes)
+	if err != nil {
+		return 0, err
+	}
+	if n != Int16Size {
+		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 2")
+	}
+	if endianness == BigEndian {
+		return int16(binary.BigEndian.Uint16(_bytes)), nil
+	}
+	return int16(binary.LittleEndian.Uint16(_bytes)), nil
+}
+
+func legacyReadInt24(buf *bytes.Buffer, endianness Endianness) (int32, error) {
+	_bytes := make([]byte, 3)
+	n, err := buf.Read(_bytes)
+	if err != nil {
+		return 0, err
+	}
+	if n != Int24Size {
+		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 3")
+	}
+	uint24 := int32(uint32(_bytes[2]) | uint32(_bytes[1])<<8 | uint32(_bytes[0])<<16)
+	if endianness == LittleEndian {
+		uint24 = int32(uint32(_bytes[0]) | uint32(_bytes[1])<<8 | uint32(_bytes[2])<<16)
+	}
+	if uint24&0x800000 == 0 {
+		return uint24, nil
+	}
+	return uint24 - 0x1000000, nil
+}
+
+func legacyReadInt32(buf *bytes.Buffer, endianness Endianness) (int32, error) {
+	_bytes := make([]byte, 4)
+	n, err := buf.Read(_bytes)
+	if err != nil {
+		return 0, err
+	}
+	if n != Int32Size {
+		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 4")
+	}
+	if endianness == BigEndian {
+		return int32(binary.BigEndian.Uint32(_bytes)), nil
+	}
+	return int32(binary.LittleEndian.Uint32(_bytes)), nil
+}
+
+func legacyReadInt64(buf *bytes.Buffer, endianness Endianness) (int64, error) {
+	_bytes := make([]byte, 8)
+	n, err := buf.Read(_bytes)
+	if err != nil {
+		return 0, err
+	}
+	if n != Int64Size {
+		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 8")
+	}
+	if endianness == BigEndian {
+		return int64(binary.BigEndian.Uint64(_bytes)), nil
+	}
+	return int64(binary.LittleEndian.Uint64(_bytes)), nil
+}
+
+func legacyWrite(buf *bytes.Buffer, data any, endianness Endianness) error {
+	if endianness == BigEndian {
+		return binary.Write(buf, binary.BigEndian, data)
+	}
+	return binary.Write(buf, binary.LittleEndian, data)
+}
+
+func legacyWriteInt24(buf *bytes.Buffer, data int32, endianness Endianness) error {
+	if data > 0x7FFFFF {
+		return fmt.Errorf("int24 overflow")
+	}
+	if endianness == BigEndian {
+		buf.WriteByte(byte(data >> 16))
+		buf.WriteByte(byte(data >> 8))
+		buf.WriteByte(byte(data))
+	} else {
+		buf.WriteByte(byte(data))
+		buf.WriteByte(byte(data >> 8))
+		buf.WriteByte(byte(data >> 16))
+	}
+	return nil
+}
+
+// benchData is read over and over, the Reader is only recreated when it runs out.
+var benchData = make([]byte, 1<<16)
+
+func benchRead(b *testing.B, size int, legacy func(buf *bytes.Buffer) error, current func(r *Reader) error) {
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(size))
+		buf := bytes.NewBuffer(benchData)
+		for i := 0; i < b.N; i++ {
+			if buf.Len() < size {
+				buf = bytes.NewBuffer(benchData)
+			}
+			if err := legacy(buf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("current", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(size))
+		r := NewReader(benchData)
+		for i := 0; i < b.N; i++ {
+			if r.Remaining() < size {
+				r = NewReader(benchData)
+			}
+			if err := current(r); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func benchWrite(b *testing.B, size int, legacy func(buf *bytes.Buffer) error, current func(w *Writer) error) {
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(size))
+		buf := bytes.NewBuffer(make([]byte, 0, len(benchData)))
+		for i := 0; i < b.N; i++ {
+			if buf.Len()+size > len(benchData) {
+				buf.Reset()
+			}
+			if err := legacy(buf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("current", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(size))
+		w := &Writer{Buffer: bytes.NewBuffer(make([]byte, 0, len(benchData)))}
+		for i := 0; i < b.N; i++ {
+			if w.Buffer.Len()+size > len(benchData) {
+				w.Buffer.Reset()
+			}
+			if err := current(w); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkReader_ReadInt16(b *testing.B) {
+	benchRead(b, Int16Size,
+		func(buf *bytes.Buffer) error { _, err := legacyReadInt16(buf, BigEndian); return err },
+		func(r *Reader) error { _, err := r.ReadInt16(BigEndian); return err })
+}
+
+func BenchmarkReader_ReadInt24(b *testing.B) {
+	benchRead(b, Int24Size,
+		func(buf *bytes.Buffer) error { _, err := legacyReadInt24(buf, BigEndian); return err },
+		func(r *Reader) error { _, err := r.ReadInt24(BigEndian); return err })
+}
+
+func BenchmarkReader_ReadInt32(b *testing.B) {
+	benchRead(b, Int32Size,
+		func(buf *bytes.Buffer) error { _, err := legacyReadInt32(buf, BigEndian); return err },
+		func(r *Reader) error { _, err := r.ReadInt32(BigEndian); return err })
+}
+
+func BenchmarkReader_ReadInt64(b *testing.B) {
+	benchRead(b, Int64Size,
+		func(buf *bytes.Buffer) error { _, err := legacyReadInt64(buf, LittleEndian); return err },
+		func(r *Reader) error { _, err := r.ReadInt64(LittleEndian); return err })
+}
+
+func BenchmarkWriter_WriteInt16(b *testing.B) {
+	benchWrite(b, Int16Size,
+		func(buf *bytes.Buffer) error { return legacyWrite(buf, int16(1234), BigEndian) },
+		func(w *Writer) error { return w.WriteInt16(1234, BigEndian) })
+}
+
+func BenchmarkWriter_WriteInt24(b *testing.B) {
+	benchWrite(b, Int24Size,
+		func(buf *bytes.Buffer) error { return legacyWriteInt24(buf, 123456, BigEndian) },
+		func(w *Writer) error { return w.WriteInt24(123456, BigEndian) })
+}
+
+func BenchmarkWriter_WriteInt32(b *testing.B) {
+	benchWrite(b, Int32Size,
+		func(buf *bytes.Buffer) error { return legacyWrite(buf, int32(123456), BigEndian) },
+		func(w *Writer) error { return w.WriteInt32(123456, BigEndian) })
+}
+
+func BenchmarkWriter_WriteInt64(b *testing.B) {
+	benchWrite(b, Int64Size,
+		func(buf *bytes.Buffer) error { return legacyWrite(buf, int64(123456789), LittleEndian) },
+		func(w *Writer) error { return w.WriteInt64(123456789, LittleEndian) })
+}
+
+// BenchmarkPacket reads and writes a typical message: an id, a LogicLong, a few ints and a short string.
+func BenchmarkPacket(b *testing.B) {
+	const size = Int16Size + 2*Int32Size + Int32Size + Int64Size + Int32Size + 5
+	benchWrite(b, size,
+		func(buf *bytes.Buffer) error {
+			legacyWrite(buf, uint16(10101), BigEndian)
+			legacyWrite(buf, int32(1), BigEndian)
+			legacyWrite(buf, int32(2), BigEndian)
+			legacyWrite(buf, int32(3000), BigEndian)
+			legacyWrite(buf, int64(1656676200), BigEndian)
+			legacyWrite(buf, int32(5), BigEndian)
+			_, err := buf.WriteString("hello")
+			return err
+		},
+		func(w *Writer) error {
+			w.WriteUInt16(10101, BigEndian)
+			w.WriteLogicLong(LogicLong{High: 1, Low: 2}, BigEndian)
+			w.WriteInt32(3000, BigEndian)
+			w.WriteInt64(1656676200, BigEndian)
+			return w.WriteString("hello")
+		})
+}
+
+func TestAllocs(t *testing.T) {
+	r := NewReader(make([]byte, 1<<20))
+	w := &Writer{Buffer: bytes.NewBuffer(make([]byte, 0, 1<<20))}
+	tests := map[string]func(){
+		"ReadByte":       func() { r.ReadByte() },
+		"ReadBool":       func() { r.ReadBool() },
+		"ReadBoolean":    func() { r.ReadBoolean() },
+		"ReadInt8":       func() { r.ReadInt8() },
+		"ReadInt16":      func() { r.ReadInt16(BigEndian) },
+		"ReadUInt24":     func() { r.ReadUInt24(LittleEndian) },
+		"ReadInt32":      func() { r.ReadInt32(BigEndian) },
+		"ReadInt64":      func() { r.ReadInt64(LittleEndian) },
+		"ReadIntSize":    func() { r.ReadIntSize(5, BigEndian) },
+		"ReadFloat32":    func() { r.ReadFloat32(BigEndian) },
+		"ReadFloat64":    func() { r.ReadFloat64(LittleEndian) },
+		"ReadVarInt":     func() { r.ReadVarInt() },
+		"ReadUVarInt":    func() { r.ReadUVarInt() },
+		"ReadRRSInt32":   func() { r.ReadRRSInt32() },
+		"ReadRRSLong":    func() { r.ReadRRSLong() },
+		"ReadLogicLong":  func() { r.ReadLogicLong(BigEndian) },
+		"ReadLong":       func() { r.ReadLong(BigEndian) },
+		"WriteBool":      func() { w.WriteBool(true, 1) },
+		"WriteBoolean":   func() { w.WriteBoolean(true) },
+		"WriteInt8":      func() { w.WriteInt8(-1) },
+		"WriteInt16":     func() { w.WriteInt16(-1, BigEndian) },
+		"WriteUInt24":    func() { w.WriteUInt24(0xABCDEF, LittleEndian) },
+		"WriteInt32":     func() { w.WriteInt32(-1, BigEndian) },
+		"WriteInt64":     func() { w.WriteInt64(-1, LittleEndian) },
+		"WriteIntSize":   func() { w.WriteIntSize(-1, 5, BigEndian) },
+		"WriteFloat32":   func() { w.WriteFloat32(1.5, BigEndian) },
+		"WriteFloat64":   func() { w.WriteFloat64(1.5, LittleEndian) },
+		"WriteVarInt":    func() { w.WriteVarInt(-300) },
+		"WriteUVarInt":   func() { w.WriteUVarInt(300) },
+		"WriteRRSInt32":  func() { w.WriteRRSInt32(-300) },
+		"WriteRRSLong":   func() { w.WriteRRSLong(LogicLong{High: 1, Low: 2}) },
+		"WriteLogicLong": func() { w.WriteLogicLong(LogicLong{High: 1, Low: 2}, BigEndian) },
+		"WriteString":    func() { w.WriteString("hello") },
+		"WriteBytes":     func() { w.WriteBytes([]byte{0x01, 0x02}) },
+	}
+	for name, f := range tests {
+		if allocs := testing.AllocsPerRun(100, f); allocs != 0 {
+			t.Errorf("%s allocates %v times, want 0", name, allocs)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package bytestream
+
+import (
+	"sort"
+	"unsafe"
+)
+
+// A ReadFunc reads one element of a collection, method expressions like (*Reader).ReadString fit as they are.
+type ReadFunc[T any] func(r *Reader) (T, error)
+
+// A WriteFunc writes one element of a collection, method expressions like (*Writer).WriteString fit as they are.
+type WriteFunc[T any] func(w *Writer, v T) error
+
+// Ordered is what WriteMap needs of its keys so they can be sorted.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// ReadSlice reads a count encoded as prefix followed by that many elements, a Null count gives back a nil slice. A count above
+// maxCount (or the Reader's MaxCollectionCount) fails with ErrLimitExceeded before anything is allocated, a maxCount of 0 means
+// there's no limit.
+func ReadSlice[T any](r *Reader, prefix LengthPrefix, maxCount int, read ReadFunc[T]) ([]T, error) {
+	count, err := r.readLength("ReadSlice", prefix, minLimit(maxCount, r.opts.MaxCollectionCount))
+	if err != nil || count < 0 {
+		return nil, err
+	}
+	var zero T
+	if err := r.allocEach("ReadSlice", count, unsafe.Sizeof(zero)); err != nil {
+		return nil, err
+	}
+	// Don't trust the count with the allocation, every element takes at least a byte in practice
+	values := make([]T, 0, minInt(count, r.Remaining()))
+	for i := 0; i < count; i++ {
+		v, err := read(r)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// WriteSlice writes the count of values followed by every element, see ReadSlice.
+func WriteSlice[T any](w *Writer, prefix LengthPrefix, values []T, write WriteFunc[T]) error {
+	if err := w.writeLength("WriteSlice", prefix, len(values), values == nil); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := write(w, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadMap reads a count encoded as prefix followed by that many key/value pairs, a Null count gives back a nil map. maxCount works like it does for
+// ReadSlice.
+func ReadMap[K comparable, V any](r *Reader, prefix LengthPrefix, maxCount int, readKey ReadFunc[K], readValue ReadFunc[V]) (map[K]V, error) {
+	count, err := r.readLength("ReadMap", prefix, minLimit(maxCount, r.opts.MaxCollectionCount))
+	if err != nil || count < 0 {
+		return nil, err
+	}
+	var zeroKey K
+	var zeroValue V
+	if err := r.allocEach("ReadMap", count, unsafe.Sizeof(zeroKey)+unsafe.Sizeof(zeroValue)); err != nil {
+		return nil, err
+	}
+	m := make(map[K]V, minInt(count, r.Remaining()))
+	for i := 0; i < count; i++ {
+		k, err := readKey(r)
+		if err != nil {
+			return nil, err
+		}
+		v, err := readValue(r)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+// WriteMap writes the count of m followed by every key/value pair, keys are written in ascending order so the same map always
+// encodes to the same bytes.
+func WriteMap[K Ordered, V any](w *Writer, prefix LengthPrefix, m map[K]V, writeKey WriteFunc[K], writeValue WriteFunc[V]) error {
+	if err := w.writeLength("WriteMap", prefix, len(m), m == nil); err != nil {
+		return err
+	}
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	for _, k := range keys {
+		if err := writeKey(w, k); err != nil {
+			return err
+		}
+		if err := writeValue(w, m[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
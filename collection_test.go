@@ -0,0 +1,144 @@
+package bytestream
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func readInt32(r *Reader) (int32, error) {
+	return r.ReadInt32(BigEndian)
+}
+
+func writeInt32(w *Writer, v int32) error {
+	return w.WriteInt32(v, BigEndian)
+}
+
+func TestReadSlice(t *testing.T) {
+	type args struct {
+		prefix   LengthPrefix
+		maxCount int
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		args    args
+		want    []int32
+		wantErr error
+	}{
+		{name: "nil int32", data: []byte{0xFF, 0xFF, 0xFF, 0xFF}, args: args{prefix: CountInt32}, want: nil, wantErr: nil},
+		{name: "empty int32", data: []byte{0x00, 0x00, 0x00, 0x00}, args: args{prefix: CountInt32}, want: []int32{}, wantErr: nil},
+		{name: "two int32", data: []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x01, 0xFF, 0xFF, 0xFF, 0xFF}, args: args{prefix: CountInt32}, want: []int32{1, -1}, wantErr: nil},
+		{name: "nil vint", data: []byte{0x40}, args: args{prefix: CountVInt}, want: nil, wantErr: nil},
+		{name: "one vint", data: []byte{0x01, 0x00, 0x00, 0x00, 0x07}, args: args{prefix: CountVInt}, want: []int32{7}, wantErr: nil},
+		{name: "one byte", data: []byte{0x01, 0x00, 0x00, 0x00, 0x07}, args: args{prefix: CountByte}, want: []int32{7}, wantErr: nil},
+		{name: "max byte", data: []byte{0xFF}, args: args{prefix: CountByte}, want: nil, wantErr: ErrShortRead},
+		{name: "at max count", data: []byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x07}, args: args{prefix: CountInt32, maxCount: 1}, want: []int32{7}, wantErr: nil},
+		{name: "over max count", data: []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x07}, args: args{prefix: CountInt32, maxCount: 1}, want: nil, wantErr: ErrLimitExceeded},
+		{name: "negative", data: []byte{0xFF, 0xFF, 0xFF, 0xFE}, args: args{prefix: CountInt32}, want: nil, wantErr: ErrInvalidLength},
+		{name: "huge count", data: []byte{0x7F, 0xFF, 0xFF, 0xFF, 0x00}, args: args{prefix: CountInt32}, want: nil, wantErr: ErrShortRead},
+		{name: "short element", data: []byte{0x00, 0x00, 0x00, 0x01, 0x00}, args: args{prefix: CountInt32}, want: nil, wantErr: ErrShortRead},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadSlice(NewReader(tt.data), tt.args.prefix, tt.args.maxCount, readInt32)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("ReadSlice() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadSlice() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteSlice(t *testing.T) {
+	type args struct {
+		prefix LengthPrefix
+		values []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "nil int32", args: args{prefix: CountInt32, values: nil}, want: []byte{0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+		{name: "empty int32", args: args{prefix: CountInt32, values: []string{}}, want: []byte{0x00, 0x00, 0x00, 0x00}, wantErr: false},
+		{name: "one int32", args: args{prefix: CountInt32, values: []string{"a"}}, want: []byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 'a'}, wantErr: false},
+		{name: "nil vint", args: args{prefix: CountVInt, values: nil}, want: []byte{0x40}, wantErr: false},
+		{name: "one vint", args: args{prefix: CountVInt, values: []string{"a"}}, want: []byte{0x01, 0x00, 0x00, 0x00, 0x01, 'a'}, wantErr: false},
+		{name: "nil byte", args: args{prefix: CountByte, values: nil}, want: []byte{0x00}, wantErr: false},
+		{name: "too many for a byte", args: args{prefix: CountByte, values: make([]string, 256)}, want: []byte{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter()
+			if err := WriteSlice(w, tt.args.prefix, tt.args.values, (*Writer).WriteString); (err != nil) != tt.wantErr {
+				t.Errorf("WriteSlice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(w.Buffer.Bytes(), tt.want) {
+				t.Errorf("WriteSlice() wrote %v, want %v", w.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  LengthPrefix
+		data    []byte
+		want    map[string]int32
+		wantErr bool
+	}{
+		{name: "nil", prefix: CountInt32, data: []byte{0xFF, 0xFF, 0xFF, 0xFF}, want: nil, wantErr: false},
+		{name: "empty", prefix: CountInt32, data: []byte{0x00, 0x00, 0x00, 0x00}, want: map[string]int32{}, wantErr: false},
+		{name: "one", prefix: CountVInt, data: []byte{0x01, 0x00, 0x00, 0x00, 0x01, 'a', 0x00, 0x00, 0x00, 0x02}, want: map[string]int32{"a": 2}, wantErr: false},
+		{name: "short value", prefix: CountVInt, data: []byte{0x01, 0x00, 0x00, 0x00, 0x01, 'a', 0x00}, want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadMap(NewReader(tt.data), tt.prefix, 0, (*Reader).ReadString, readInt32)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadMap() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadMap() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteMap(t *testing.T) {
+	m := map[int32]string{30: "c", 10: "a", -5: "z", 20: "b"}
+	want := []byte{0x04,
+		0xFF, 0xFF, 0xFF, 0xFB, 0x00, 0x00, 0x00, 0x01, 'z',
+		0x00, 0x00, 0x00, 0x0A, 0x00, 0x00, 0x00, 0x01, 'a',
+		0x00, 0x00, 0x00, 0x14, 0x00, 0x00, 0x00, 0x01, 'b',
+		0x00, 0x00, 0x00, 0x1E, 0x00, 0x00, 0x00, 0x01, 'c',
+	}
+	// Map iteration order is random, every run has to come out the same
+	for i := 0; i < 10; i++ {
+		w := NewWriter()
+		if err := WriteMap(w, CountByte, m, writeInt32, (*Writer).WriteString); err != nil {
+			t.Fatalf("WriteMap() error = %v", err)
+		}
+		if !bytes.Equal(w.Buffer.Bytes(), want) {
+			t.Fatalf("WriteMap() wrote %v, want %v", w.Buffer.Bytes(), want)
+		}
+	}
+
+	got, err := ReadMap(NewReader(want), CountByte, 4, readInt32, (*Reader).ReadString)
+	if err != nil || !reflect.DeepEqual(got, m) {
+		t.Errorf("ReadMap() = %v, %v, want %v", got, err, m)
+	}
+
+	w := NewWriter()
+	if err := WriteMap[string, int32](w, CountInt32, nil, (*Writer).WriteString, writeInt32); err != nil || !bytes.Equal(w.Buffer.Bytes(), []byte{0xFF, 0xFF, 0xFF, 0xFF}) {
+		t.Errorf("WriteMap() nil wrote %v, %v, want -1", w.Buffer.Bytes(), err)
+	}
+}
@@ -0,0 +1,105 @@
+package bytestream
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+)
+
+// The compression levels the Compressor implementations accept, they're the compress/flate ones except for NoCompression: a Level
+// of 0 is the one left unset and means DefaultCompression.
+const (
+	NoCompression      = flate.HuffmanOnly - 1
+	BestSpeed          = flate.BestSpeed
+	BestCompression    = flate.BestCompression
+	DefaultCompression = flate.DefaultCompression
+	HuffmanOnly        = flate.HuffmanOnly
+)
+
+// A Compressor is the algorithm behind the compressed strings and byte arrays, the size header around the data stays the same whichever
+// one is used.
+type Compressor interface {
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// ZlibCompressor is zlib (RFC 1950), what the game uses. A zero Level is DefaultCompression, Dict is an optional preset dictionary
+// that both sides have to agree on.
+type ZlibCompressor struct {
+	Level int
+	Dict  []byte
+}
+
+func (c ZlibCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zlib.NewWriterLevelDict(w, flateLevel(c.Level), c.Dict)
+}
+
+func (c ZlibCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return zlib.NewReaderDict(r, c.Dict)
+}
+
+// DeflateCompressor is raw deflate (RFC 1951) without any header or checksum, Level and Dict work like they do for ZlibCompressor.
+type DeflateCompressor struct {
+	Level int
+	Dict  []byte
+}
+
+func (c DeflateCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return flate.NewWriterDict(w, flateLevel(c.Level), c.Dict)
+}
+
+func (c DeflateCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return flate.NewReaderDict(r, c.Dict), nil
+}
+
+// GzipCompressor is gzip (RFC 1952), Level works like it does for ZlibCompressor. gzip has no preset dictionaries.
+type GzipCompressor struct {
+	Level int
+}
+
+func (c GzipCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, flateLevel(c.Level))
+}
+
+func (c GzipCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// flateLevel turns a Level into the compress/flate one.
+func flateLevel(level int) int {
+	switch level {
+	case 0:
+		return flate.DefaultCompression
+	case NoCompression:
+		return flate.NoCompression
+	}
+	return level
+}
+
+type noCompressor struct{}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func (noCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return nopWriteCloser{w}, nil
+}
+
+func (noCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(r), nil
+}
+
+var (
+	// Zlib is what ReadCompressedString and WriteCompressedString use.
+	Zlib Compressor = ZlibCompressor{Level: DefaultCompression}
+	// Deflate is raw deflate at the default level.
+	Deflate Compressor = DeflateCompressor{Level: DefaultCompression}
+	// Gzip is gzip at the default level.
+	Gzip Compressor = GzipCompressor{Level: DefaultCompression}
+	// None stores the data as it is, the size header is still written.
+	None Compressor = noCompressor{}
+)
@@ -0,0 +1,158 @@
+package bytestream
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCompressor_RoundTrip(t *testing.T) {
+	dict := []byte("abcdefghijklmnopqrstuvwxyz")
+	compressors := map[string]Compressor{
+		"zlib":              Zlib,
+		"zlib best":         ZlibCompressor{Level: BestCompression},
+		"zlib stored":       ZlibCompressor{Level: NoCompression},
+		"zlib dict":         ZlibCompressor{Level: DefaultCompression, Dict: dict},
+		"deflate":           Deflate,
+		"deflate speed":     DeflateCompressor{Level: BestSpeed},
+		"deflate dict":      DeflateCompressor{Level: DefaultCompression, Dict: dict},
+		"gzip":              Gzip,
+		"gzip huffman only": GzipCompressor{Level: HuffmanOnly},
+		"none":              None,
+	}
+	payloads := [][]byte{
+		{},
+		[]byte("a"),
+		[]byte("abcdefghijklmnopqrstuvwxyz1234567890"),
+		bytes.Repeat([]byte("hello there! "), 500),
+	}
+	for name, c := range compressors {
+		t.Run(name, func(t *testing.T) {
+			for _, payload := range payloads {
+				w := NewWriter()
+				if err := w.WriteCompressedBytesWith(payload, c); err != nil {
+					t.Fatalf("Writer.WriteCompressedBytesWith() error = %v", err)
+				}
+				if err := w.WriteCompressedStringWith(string(payload), c); err != nil {
+					t.Fatalf("Writer.WriteCompressedStringWith() error = %v", err)
+				}
+				r := NewReader(w.Buffer.Bytes())
+				got, err := r.ReadCompressedBytesWith(c)
+				if err != nil || !bytes.Equal(got, payload) || got == nil {
+					t.Errorf("Reader.ReadCompressedBytesWith() = %v, %v, want %v", len(got), err, len(payload))
+				}
+				s, err := r.ReadCompressedStringWith(c)
+				if err != nil || s != string(payload) {
+					t.Errorf("Reader.ReadCompressedStringWith() = %v, %v, want %v", len(s), err, len(payload))
+				}
+				if r.Remaining() != 0 {
+					t.Errorf("Reader.Remaining() = %v, want 0", r.Remaining())
+				}
+			}
+		})
+	}
+}
+
+func TestCompressor_Level(t *testing.T) {
+	payload := bytes.Repeat([]byte("hello there! "), 500)
+	tests := []struct {
+		name       string
+		c          Compressor
+		compressed bool
+	}{
+		{name: "zlib unset", c: ZlibCompressor{Dict: []byte("hello")}, compressed: true},
+		{name: "zlib stored", c: ZlibCompressor{Level: NoCompression}, compressed: false},
+		{name: "deflate unset", c: DeflateCompressor{}, compressed: true},
+		{name: "deflate stored", c: DeflateCompressor{Level: NoCompression}, compressed: false},
+		{name: "gzip unset", c: GzipCompressor{}, compressed: true},
+		{name: "gzip stored", c: GzipCompressor{Level: NoCompression}, compressed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter()
+			if err := w.WriteCompressedBytesWith(payload, tt.c); err != nil {
+				t.Fatalf("Writer.WriteCompressedBytesWith() error = %v", err)
+			}
+			if compressed := w.Buffer.Len() < len(payload); compressed != tt.compressed {
+				t.Errorf("Writer.WriteCompressedBytesWith() wrote %v bytes of %v, want compressed %v", w.Buffer.Len(), len(payload), tt.compressed)
+			}
+		})
+	}
+}
+
+func TestCompressor_None(t *testing.T) {
+	w := NewWriter()
+	if err := w.WriteCompressedBytesWith([]byte{0xAB, 0xCD}, None); err != nil {
+		t.Fatalf("Writer.WriteCompressedBytesWith() error = %v", err)
+	}
+	want := []byte{0x00, 0x00, 0x00, 0x02, 0x02, 0x00, 0x00, 0x00, 0xAB, 0xCD}
+	if !bytes.Equal(w.Buffer.Bytes(), want) {
+		t.Errorf("Writer.WriteCompressedBytesWith() wrote %v, want %v", w.Buffer.Bytes(), want)
+	}
+}
+
+func TestCompressor_Mismatch(t *testing.T) {
+	w := NewWriter()
+	if err := w.WriteCompressedBytesWith([]byte("hello"), ZlibCompressor{Level: DefaultCompression, Dict: []byte("hello")}); err != nil {
+		t.Fatalf("Writer.WriteCompressedBytesWith() error = %v", err)
+	}
+	data := w.Buffer.Bytes()
+	if _, err := NewReader(data).ReadCompressedBytes(); !errors.Is(err, ErrDecompress) {
+		t.Errorf("Reader.ReadCompressedBytes() without the dictionary error = %v, want %v", err, ErrDecompress)
+	}
+	if _, err := NewReader(data).ReadCompressedBytesWith(Gzip); !errors.Is(err, ErrDecompress) {
+		t.Errorf("Reader.ReadCompressedBytesWith() gzip error = %v, want %v", err, ErrDecompress)
+	}
+	if err := NewWriter().WriteCompressedBytesWith([]byte("a"), ZlibCompressor{Level: 42}); err == nil {
+		t.Errorf("Writer.WriteCompressedBytesWith() with an invalid level error = nil, want an error")
+	}
+}
+
+func TestReader_ReadCompressedBytes(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    []byte
+		wantErr bool
+	}{
+		{name: "nil", fields: fields{Reader: bytes.NewBuffer([]byte{})}, want: nil, wantErr: true},
+		{name: "null", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFF})}, want: nil, wantErr: false},
+		{name: "empty", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x00, 0x78, 0x9c, 0x01, 0x00, 0x00, 0xff, 0xff, 0x00, 0x00, 0x00, 0x01})}, want: []byte{}, wantErr: false},
+		{name: "one", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x0d, 0x01, 0x00, 0x00, 0x00, 0x78, 0x9c, 0x4a, 0x04, 0x04, 0x00, 0x00, 0xff, 0xff, 0x00, 0x62, 0x00, 0x62})}, want: []byte{'a'}, wantErr: false},
+		{name: "wrong size", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x0d, 0x02, 0x00, 0x00, 0x00, 0x78, 0x9c, 0x4a, 0x04, 0x04, 0x00, 0x00, 0xff, 0xff, 0x00, 0x62, 0x00, 0x62})}, want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			got, err := r.ReadCompressedBytes()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadCompressedBytes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !bytes.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("Reader.ReadCompressedBytes() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteCompressedBytes(t *testing.T) {
+	w := NewWriter()
+	if err := w.WriteCompressedBytes(nil); err != nil || !bytes.Equal(w.Buffer.Bytes(), []byte{0xFF, 0xFF, 0xFF, 0xFF}) {
+		t.Errorf("Writer.WriteCompressedBytes() nil wrote %v, %v, want -1", w.Buffer.Bytes(), err)
+	}
+	w = NewWriter()
+	if err := w.WriteCompressedBytes([]byte("hello there!")); err != nil {
+		t.Fatalf("Writer.WriteCompressedBytes() error = %v", err)
+	}
+	// Bytes and strings share the same format
+	s, err := NewReader(w.Buffer.Bytes()).ReadCompressedString()
+	if err != nil || s != "hello there!" {
+		t.Errorf("Reader.ReadCompressedString() = %v, %v, want hello there!", s, err)
+	}
+}
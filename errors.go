@@ -0,0 +1,90 @@
+package bytestream
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// These are the kinds of failure an *Error can report, check for them with errors.Is.
+var (
+	// ErrShortRead means the stream ended (or the io.Reader failed) before everything could be read.
+	ErrShortRead = errors.New("short read")
+	// ErrInvalidLength means a length, size or count was negative or otherwise unusable.
+	ErrInvalidLength = errors.New("invalid length")
+	// ErrOverflow means a value doesn't fit in the width it's being written or read as.
+	ErrOverflow = errors.New("overflow")
+	// ErrDecompress means compressed data was corrupt or didn't match its declared size.
+	ErrDecompress = errors.New("decompression failed")
+	// ErrInvalidSeek means a Seek, Skip or ResetToMark went somewhere the Reader can't go.
+	ErrInvalidSeek = errors.New("invalid seek")
+	// ErrInvalidTag means a hashtag or LogicLong couldn't be converted.
+	ErrInvalidTag = errors.New("invalid tag")
+	// ErrFrameTooLarge means a frame's payload is longer than the FrameReader or FrameWriter allows.
+	ErrFrameTooLarge = errors.New("frame too large")
+	// ErrLimitExceeded means a length, count or size went over one of the ReaderOptions limits.
+	ErrLimitExceeded = errors.New("limit exceeded")
+	// ErrDuplicateMessage means a message id or type was registered twice.
+	ErrDuplicateMessage = errors.New("duplicate message")
+	// ErrUnknownMessage means a message type was never registered.
+	ErrUnknownMessage = errors.New("unknown message")
+	// ErrReservation means a Placeholder or section was misused: flushed before it was filled in, patched twice, or ended without
+	// being begun.
+	ErrReservation = errors.New("invalid reservation")
+	// ErrTrailingData means there were bytes left over after something should have been read completely.
+	ErrTrailingData = errors.New("trailing data")
+)
+
+// An Error is returned by every Read* and Write* method when it fails, it says which method failed and where in the stream.
+type Error struct {
+	// Op is the method that failed, e.g. ReadInt32.
+	Op string
+	// Offset is how many bytes into the stream the failure happened.
+	Offset int64
+	// Expected and Actual are the sizes involved when they're known (bytes wanted vs bytes available, declared vs decompressed size...).
+	Expected int64
+	Actual   int64
+	// Kind is one of the Err* values above, it's nil when the underlying io.Reader or io.Writer failed on its own.
+	Kind error
+	// Err is the underlying error, if there is one.
+	Err error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s at offset %d", e.Op, e.Offset)
+	if e.Kind != nil {
+		msg += ": " + e.Kind.Error()
+	}
+	if e.Expected != 0 || e.Actual != 0 {
+		msg += fmt.Sprintf(" (expected %d, got %d)", e.Expected, e.Actual)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Is matches the Kind of e, a short read that hit the end of the data also matches io.EOF (nothing was left) or io.ErrUnexpectedEOF.
+func (e *Error) Is(target error) bool {
+	if e.Kind != nil && target == e.Kind {
+		return true
+	}
+	if e.Kind == ErrShortRead && e.Err == nil {
+		return (target == io.EOF && e.Actual == 0) || (target == io.ErrUnexpectedEOF && e.Actual > 0)
+	}
+	return false
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// fail builds an *Error for the current position of the Reader.
+func (r *Reader) fail(op string, kind error, expected, actual int64, err error) *Error {
+	return &Error{Op: op, Offset: r.offset, Expected: expected, Actual: actual, Kind: kind, Err: err}
+}
+
+// fail builds an *Error for the current position of the Writer.
+func (w *Writer) fail(op string, kind error, expected, actual int64, err error) *Error {
+	return &Error{Op: op, Offset: w.Offset(), Expected: expected, Actual: actual, Kind: kind, Err: err}
+}
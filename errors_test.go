@@ -0,0 +1,189 @@
+package bytestream
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestReader_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		read     func(r *Reader) error
+		kind     error
+		op       string
+		offset   int64
+		expected int64
+		actual   int64
+	}{
+		{
+			name:     "truncated int32",
+			data:     []byte{0x00, 0x01, 0x00, 0x00},
+			read:     func(r *Reader) error { r.ReadInt16(BigEndian); _, err := r.ReadInt32(BigEndian); return err },
+			kind:     ErrShortRead,
+			op:       "ReadInt32",
+			offset:   2,
+			expected: 4,
+			actual:   2,
+		},
+		{
+			name:     "truncated string",
+			data:     []byte{0x00, 0x00, 0x00, 0x05, 'h', 'i'},
+			read:     func(r *Reader) error { _, err := r.ReadString(); return err },
+			kind:     ErrShortRead,
+			op:       "ReadString",
+			offset:   4,
+			expected: 5,
+			actual:   2,
+		},
+		{
+			name:   "negative string length",
+			data:   []byte{0xFF, 0xFF, 0xFF, 0xFE},
+			read:   func(r *Reader) error { _, err := r.ReadString(); return err },
+			kind:   ErrInvalidLength,
+			op:     "ReadString",
+			offset: 4,
+			actual: -2,
+		},
+		{
+			name:     "invalid size",
+			data:     []byte{0x01},
+			read:     func(r *Reader) error { _, err := r.ReadUIntSize(9, BigEndian); return err },
+			kind:     ErrInvalidLength,
+			op:       "ReadUIntSize",
+			expected: 8,
+			actual:   9,
+		},
+		{
+			name:     "uvarint too long",
+			data:     bytes.Repeat([]byte{0xFF}, 11),
+			read:     func(r *Reader) error { _, err := r.ReadUVarInt(); return err },
+			kind:     ErrOverflow,
+			op:       "ReadUVarInt",
+			offset:   10,
+			expected: 10,
+			actual:   11,
+		},
+		{
+			name:     "decompressed size mismatch",
+			data:     []byte{0x00, 0x00, 0x00, 0x09, 0x05, 0x00, 0x00, 0x00, 0x78, 0x9C, 0x4B, 0x04, 0x00, 0x00, 0x62, 0x00, 0x62},
+			read:     func(r *Reader) error { _, err := r.ReadCompressedString(); return err },
+			kind:     ErrDecompress,
+			op:       "ReadCompressedString",
+			offset:   17,
+			expected: 5,
+			actual:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.read(NewReader(tt.data))
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("error = %v, want %v", err, tt.kind)
+			}
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("error = %T, want *Error", err)
+			}
+			if e.Op != tt.op || e.Offset != tt.offset || e.Expected != tt.expected || e.Actual != tt.actual {
+				t.Errorf("error = %+v, want op %v offset %v expected %v actual %v", e, tt.op, tt.offset, tt.expected, tt.actual)
+			}
+		})
+	}
+}
+
+func TestReader_ErrorsEOF(t *testing.T) {
+	_, err := NewReader([]byte{}).ReadInt32(BigEndian)
+	if !errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Reader.ReadInt32() on no data error = %v, want io.EOF", err)
+	}
+	_, err = NewReader([]byte{0x01}).ReadInt32(BigEndian)
+	if !errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		t.Errorf("Reader.ReadInt32() on 1 byte error = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestWriter_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		write    func(w *Writer) error
+		kind     error
+		op       string
+		offset   int64
+		expected int64
+		actual   int64
+	}{
+		{
+			name:     "int24 overflow",
+			write:    func(w *Writer) error { w.WriteInt8(1); return w.WriteInt24(1<<23, BigEndian) },
+			kind:     ErrOverflow,
+			op:       "WriteInt24",
+			offset:   1,
+			expected: 3,
+			actual:   4,
+		},
+		{
+			name:     "uint24 overflow",
+			write:    func(w *Writer) error { return w.WriteUInt24(0x01000000, BigEndian) },
+			kind:     ErrOverflow,
+			op:       "WriteUInt24",
+			expected: 3,
+			actual:   4,
+		},
+		{
+			name:     "invalid size",
+			write:    func(w *Writer) error { return w.WriteIntSize(1, 0, BigEndian) },
+			kind:     ErrInvalidLength,
+			op:       "WriteIntSize",
+			expected: 8,
+		},
+		{
+			name:     "string too long for its size",
+			write:    func(w *Writer) error { return w.WriteStringSize(string(make([]byte, 128)), 1) },
+			kind:     ErrOverflow,
+			op:       "WriteStringSize",
+			expected: 1,
+			actual:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.write(NewWriter())
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("error = %v, want %v", err, tt.kind)
+			}
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("error = %T, want *Error", err)
+			}
+			if e.Op != tt.op || e.Offset != tt.offset || e.Expected != tt.expected || e.Actual != tt.actual {
+				t.Errorf("error = %+v, want op %v offset %v expected %v actual %v", e, tt.op, tt.offset, tt.expected, tt.actual)
+			}
+		})
+	}
+}
+
+func TestWriter_ErrorsSticky(t *testing.T) {
+	errBroken := errors.New("broken pipe")
+	w := NewWriterToSize(&failingWriter{err: errBroken}, 1)
+	err := w.WriteInt32(1, BigEndian)
+	var e *Error
+	if !errors.As(err, &e) || !errors.Is(err, errBroken) || e.Op != "WriteInt32" {
+		t.Fatalf("Writer.WriteInt32() error = %v, want WriteInt32 wrapping %v", err, errBroken)
+	}
+	err = w.WriteString("hi")
+	if !errors.As(err, &e) || !errors.Is(err, errBroken) || e.Op != "WriteString" {
+		t.Errorf("Writer.WriteString() error = %v, want WriteString wrapping %v", err, errBroken)
+	}
+}
+
+func TestIDToTag_Errors(t *testing.T) {
+	if _, err := IDToTag(256, 1); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("IDToTag() error = %v, want %v", err, ErrInvalidTag)
+	}
+	if _, _, err := TagToID("#ABC"); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("TagToID() error = %v, want %v", err, ErrInvalidTag)
+	}
+}
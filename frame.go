@@ -0,0 +1,152 @@
+package bytestream
+
+import (
+	"errors"
+	"io"
+)
+
+const (
+	// FrameHeaderSize is the size of the header in front of every message: a 2-byte id, a 3-byte length and a 2-byte version.
+	FrameHeaderSize = Int16Size + Int24Size + Int16Size
+	// MaxFrameLength is the longest payload the 3-byte length can describe.
+	MaxFrameLength = 1<<24 - 1
+)
+
+// A Frame is one message off the wire, the header is always big endian.
+type Frame struct {
+	ID      uint16
+	Version uint16
+	Payload []byte
+
+	// from is the Reader the frame was read from, if any, so the payload is read with its limits.
+	from *Reader
+}
+
+// Reader returns a Reader positioned at the start of the payload. For a frame returned by ReadFrame it works like a sub-reader: it
+// has the options and byte order of the Reader the frame came from, and what it allocates is taken out of that Reader's budget.
+func (f *Frame) Reader() *Reader {
+	r := NewReader(f.Payload)
+	if f.from != nil {
+		r.opts, r.order = f.from.opts, f.from.order
+		r.budget = f.from
+		if f.from.budget != nil {
+			r.budget = f.from.budget
+		}
+	}
+	return r
+}
+
+// ReadFrame reads a header and its payload, a maxLength of 0 (or anything above MaxFrameLength) means MaxFrameLength. A payload
+// longer than maxLength fails with ErrFrameTooLarge and is skipped without being buffered whole, so the next ReadFrame starts at
+// the next frame. It returns an error matching io.EOF only when there wasn't a single byte left for the next frame.
+func (r *Reader) ReadFrame(maxLength int) (*Frame, error) {
+	const op = "ReadFrame"
+	if maxLength <= 0 || maxLength > MaxFrameLength {
+		maxLength = MaxFrameLength
+	}
+	header, err := r.next(op, FrameHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+	frame := &Frame{
+		ID:      uint16(header[0])<<8 | uint16(header[1]),
+		Version: uint16(header[5])<<8 | uint16(header[6]),
+		from:    r,
+	}
+	length := int(header[2])<<16 | int(header[3])<<8 | int(header[4])
+	var rejected error
+	if length > maxLength {
+		rejected = r.fail(op, ErrFrameTooLarge, int64(maxLength), int64(length), nil)
+	} else {
+		// The payload gets copied out below, so it counts like any other byte array
+		rejected = r.alloc(op, int64(length), r.opts.MaxBytesLength)
+	}
+	if rejected != nil {
+		// Leave the stream at the next header, if the payload isn't all there the stream is over anyway
+		r.skip(op, length)
+		return nil, rejected
+	}
+
+	payload, err := r.next(op, length)
+	if err != nil {
+		// The header was there so running out now is never a clean EOF
+		var e *Error
+		if errors.As(err, &e) && e.Err == nil && errors.Is(e, io.EOF) {
+			e.Err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	// The Reader reuses its buffer, the frame needs its own copy
+	frame.Payload = append([]byte{}, payload...)
+	return frame, nil
+}
+
+// WriteFrame writes the header for f followed by its payload.
+func (w *Writer) WriteFrame(f *Frame) error {
+	return w.writeFrame("WriteFrame", f, MaxFrameLength)
+}
+
+func (w *Writer) writeFrame(op string, f *Frame, maxLength int) error {
+	if len(f.Payload) > maxLength {
+		return w.fail(op, ErrFrameTooLarge, int64(maxLength), int64(len(f.Payload)), nil)
+	}
+	if err := w.writeUInt(op, uint64(f.ID), Int16Size, BigEndian); err != nil {
+		return err
+	}
+	if err := w.writeUInt(op, uint64(len(f.Payload)), Int24Size, BigEndian); err != nil {
+		return err
+	}
+	if err := w.writeUInt(op, uint64(f.Version), Int16Size, BigEndian); err != nil {
+		return err
+	}
+	return w.write(op, f.Payload)
+}
+
+// A FrameReader reads frames one after another from an io.Reader, a frame split across several reads (like a TCP segment) is put
+// back together before it's returned.
+type FrameReader struct {
+	r         *Reader
+	maxLength int
+}
+
+// NewFrameReader returns a FrameReader for src that rejects payloads longer than maxLength, 0 or anything above MaxFrameLength means
+// MaxFrameLength.
+func NewFrameReader(src io.Reader, maxLength int) *FrameReader {
+	if maxLength <= 0 || maxLength > MaxFrameLength {
+		maxLength = MaxFrameLength
+	}
+	return &FrameReader{r: NewReaderFrom(src), maxLength: maxLength}
+}
+
+// SetOptions sets the limits frames are read with, see (*Reader).SetOptions. They apply to the payloads and to the Readers returned
+// by Frame.Reader, which all share one allocation budget.
+func (fr *FrameReader) SetOptions(opts ReaderOptions) {
+	fr.r.SetOptions(opts)
+}
+
+// ReadFrame returns the next frame, see (*Reader).ReadFrame.
+func (fr *FrameReader) ReadFrame() (*Frame, error) {
+	return fr.r.ReadFrame(fr.maxLength)
+}
+
+// A FrameWriter writes frames to an io.Writer, each frame is sent with a single Write once it's complete.
+type FrameWriter struct {
+	w         *Writer
+	maxLength int
+}
+
+// NewFrameWriter returns a FrameWriter for dst that refuses payloads longer than maxLength, see NewFrameReader.
+func NewFrameWriter(dst io.Writer, maxLength int) *FrameWriter {
+	if maxLength <= 0 || maxLength > MaxFrameLength {
+		maxLength = MaxFrameLength
+	}
+	return &FrameWriter{w: NewWriterTo(dst), maxLength: maxLength}
+}
+
+// WriteFrame writes f and flushes it.
+func (fw *FrameWriter) WriteFrame(f *Frame) error {
+	if err := fw.w.writeFrame("WriteFrame", f, fw.maxLength); err != nil {
+		return err
+	}
+	return fw.w.flush("WriteFrame")
+}
@@ -0,0 +1,202 @@
+package bytestream
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReader_ReadFrame(t *testing.T) {
+	type args struct {
+		maxLength int
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		args    args
+		want    *Frame
+		wantErr error
+	}{
+		{name: "nil", data: []byte{}, args: args{maxLength: MaxFrameLength}, want: nil, wantErr: io.EOF},
+		{name: "empty payload", data: []byte{0x27, 0x10, 0x00, 0x00, 0x00, 0x00, 0x01}, args: args{maxLength: MaxFrameLength}, want: &Frame{ID: 10000, Version: 1, Payload: []byte{}}, wantErr: nil},
+		{name: "payload", data: []byte{0x4E, 0x84, 0x00, 0x00, 0x02, 0x00, 0x05, 0xAB, 0xCD}, args: args{maxLength: MaxFrameLength}, want: &Frame{ID: 20100, Version: 5, Payload: []byte{0xAB, 0xCD}}, wantErr: nil},
+		{name: "at max length", data: []byte{0x00, 0x01, 0x00, 0x00, 0x02, 0x00, 0x00, 0xAB, 0xCD}, args: args{maxLength: 2}, want: &Frame{ID: 1, Version: 0, Payload: []byte{0xAB, 0xCD}}, wantErr: nil},
+		{name: "zero max length", data: []byte{0x00, 0x01, 0x00, 0x00, 0x02, 0x00, 0x00, 0xAB, 0xCD}, args: args{maxLength: 0}, want: &Frame{ID: 1, Version: 0, Payload: []byte{0xAB, 0xCD}}, wantErr: nil},
+		{name: "too large", data: []byte{0x00, 0x01, 0x00, 0x00, 0x03, 0x00, 0x00, 0xAB, 0xCD, 0xEF}, args: args{maxLength: 2}, want: nil, wantErr: ErrFrameTooLarge},
+		{name: "truncated header", data: []byte{0x27, 0x10, 0x00}, args: args{maxLength: MaxFrameLength}, want: nil, wantErr: io.ErrUnexpectedEOF},
+		{name: "missing payload", data: []byte{0x27, 0x10, 0x00, 0x00, 0x02, 0x00, 0x01}, args: args{maxLength: MaxFrameLength}, want: nil, wantErr: io.ErrUnexpectedEOF},
+		{name: "truncated payload", data: []byte{0x27, 0x10, 0x00, 0x00, 0x02, 0x00, 0x01, 0xAB}, args: args{maxLength: MaxFrameLength}, want: nil, wantErr: io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(tt.data)
+			got, err := r.ReadFrame(tt.args.maxLength)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Reader.ReadFrame() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == io.ErrUnexpectedEOF && errors.Is(err, io.EOF) {
+				t.Errorf("Reader.ReadFrame() error = %v matches io.EOF", err)
+			}
+			if !sameFrame(got, tt.want) {
+				t.Errorf("Reader.ReadFrame() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteFrame(t *testing.T) {
+	type args struct {
+		frame *Frame
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "empty payload", args: args{frame: &Frame{ID: 10000, Version: 1}}, want: []byte{0x27, 0x10, 0x00, 0x00, 0x00, 0x00, 0x01}, wantErr: false},
+		{name: "payload", args: args{frame: &Frame{ID: 20100, Version: 5, Payload: []byte{0xAB, 0xCD}}}, want: []byte{0x4E, 0x84, 0x00, 0x00, 0x02, 0x00, 0x05, 0xAB, 0xCD}, wantErr: false},
+		{name: "too large", args: args{frame: &Frame{ID: 1, Payload: make([]byte, MaxFrameLength+1)}}, want: []byte{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter()
+			if err := w.WriteFrame(tt.args.frame); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteFrame() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(w.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteFrame() wrote %v, want %v", w.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFrameReader(t *testing.T) {
+	frames := []*Frame{
+		{ID: 10100, Version: 1, Payload: []byte("hello")},
+		{ID: 10101, Version: 0, Payload: []byte{}},
+		{ID: 24101, Version: 3, Payload: bytes.Repeat([]byte{0x42}, 5000)},
+	}
+	var buf bytes.Buffer
+	fw := NewFrameWriter(&buf, 0)
+	for _, f := range frames {
+		if err := fw.WriteFrame(f); err != nil {
+			t.Fatalf("FrameWriter.WriteFrame() error = %v", err)
+		}
+	}
+
+	srcs := map[string]func() io.Reader{
+		"whole":           func() io.Reader { return bytes.NewReader(buf.Bytes()) },
+		"one byte a time": func() io.Reader { return iotest.OneByteReader(bytes.NewReader(buf.Bytes())) },
+		"half reads":      func() io.Reader { return iotest.HalfReader(bytes.NewReader(buf.Bytes())) },
+		"data with eof":   func() io.Reader { return iotest.DataErrReader(bytes.NewReader(buf.Bytes())) },
+	}
+	for name, src := range srcs {
+		t.Run(name, func(t *testing.T) {
+			fr := NewFrameReader(src(), 0)
+			for _, want := range frames {
+				got, err := fr.ReadFrame()
+				if err != nil {
+					t.Fatalf("FrameReader.ReadFrame() error = %v", err)
+				}
+				if !sameFrame(got, want) {
+					t.Fatalf("FrameReader.ReadFrame() = %v, want %v", got.ID, want.ID)
+				}
+			}
+			if _, err := fr.ReadFrame(); !errors.Is(err, io.EOF) {
+				t.Errorf("FrameReader.ReadFrame() at the end error = %v, want %v", err, io.EOF)
+			}
+		})
+	}
+}
+
+func TestFrameReader_MaxLength(t *testing.T) {
+	data := []byte{0x27, 0x10, 0x00, 0x01, 0x00, 0x00, 0x01}
+	_, err := NewFrameReader(bytes.NewReader(data), 255).ReadFrame()
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != ErrFrameTooLarge || e.Expected != 255 || e.Actual != 256 {
+		t.Errorf("FrameReader.ReadFrame() error = %v, want %v", err, ErrFrameTooLarge)
+	}
+}
+
+func TestFrameReader_SkipsRejected(t *testing.T) {
+	w := NewWriter()
+	w.WriteFrame(&Frame{ID: 1, Payload: make([]byte, 20)})
+	w.WriteFrame(&Frame{ID: 2, Version: 3, Payload: []byte{0xAB}})
+	fr := NewFrameReader(iotest.HalfReader(bytes.NewReader(w.Buffer.Bytes())), 10)
+	if _, err := fr.ReadFrame(); !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("FrameReader.ReadFrame() error = %v, want %v", err, ErrFrameTooLarge)
+	}
+	got, err := fr.ReadFrame()
+	want := &Frame{ID: 2, Version: 3, Payload: []byte{0xAB}}
+	if err != nil || !sameFrame(got, want) {
+		t.Errorf("FrameReader.ReadFrame() after a rejected frame = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestFrameWriter_MaxLength(t *testing.T) {
+	var buf bytes.Buffer
+	fw := NewFrameWriter(&buf, 4)
+	if err := fw.WriteFrame(&Frame{ID: 1, Payload: []byte("hello")}); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("FrameWriter.WriteFrame() error = %v, want %v", err, ErrFrameTooLarge)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("FrameWriter.WriteFrame() wrote %v bytes of a rejected frame", buf.Len())
+	}
+}
+
+func TestFrame_Reader(t *testing.T) {
+	f := &Frame{ID: 10100, Payload: []byte{0x00, 0x00, 0x00, 0x02, 'h', 'i', 0x2A}}
+	r := f.Reader()
+	s, err := r.ReadString()
+	if err != nil || s != "hi" {
+		t.Fatalf("Reader.ReadString() = %v, %v, want hi", s, err)
+	}
+	b, err := r.ReadUInt8()
+	if err != nil || b != 42 {
+		t.Errorf("Reader.ReadUInt8() = %v, %v, want 42", b, err)
+	}
+}
+
+func TestFrame_ReaderOptions(t *testing.T) {
+	payload := NewWriter()
+	payload.WriteCompressedString(strings.Repeat("a", 1<<20))
+	w := NewWriter()
+	w.WriteFrame(&Frame{ID: 1, Payload: payload.Buffer.Bytes()})
+	w.WriteFrame(&Frame{ID: 2, Payload: payload.Buffer.Bytes()})
+
+	r := NewReader(w.Buffer.Bytes())
+	r.SetOptions(ReaderOptions{MaxDecompressedSize: 1 << 10})
+	f, err := r.ReadFrame(0)
+	if err != nil {
+		t.Fatalf("Reader.ReadFrame() error = %v", err)
+	}
+	if _, err := f.Reader().ReadCompressedString(); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Frame.Reader().ReadCompressedString() error = %v, want %v", err, ErrLimitExceeded)
+	}
+
+	fr := NewFrameReader(bytes.NewReader(w.Buffer.Bytes()), 0)
+	fr.SetOptions(ReaderOptions{AllocationBudget: int64(payload.Buffer.Len()) + 1<<10})
+	f, err = fr.ReadFrame()
+	if err != nil {
+		t.Fatalf("FrameReader.ReadFrame() error = %v", err)
+	}
+	if _, err := f.Reader().ReadCompressedString(); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Frame.Reader().ReadCompressedString() over the FrameReader's budget error = %v, want %v", err, ErrLimitExceeded)
+	}
+	if _, err := fr.ReadFrame(); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("FrameReader.ReadFrame() over the budget error = %v, want %v", err, ErrLimitExceeded)
+	}
+}
+
+// sameFrame compares the exported fields of two frames, ReadFrame also remembers the Reader a frame came from.
+func sameFrame(a, b *Frame) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.ID == b.ID && a.Version == b.Version && bytes.Equal(a.Payload, b.Payload)
+}
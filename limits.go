@@ -0,0 +1,72 @@
+package bytestream
+
+import "math"
+
+// ReaderOptions limits how much a Reader will allocate on behalf of whoever sent the data, every limit is checked against the length
+// the peer sent before anything is allocated and fails with ErrLimitExceeded. A limit of 0 means there isn't one.
+type ReaderOptions struct {
+	// MaxStringLength is the longest string ReadString and friends will read.
+	MaxStringLength int
+	// MaxBytesLength is the longest byte array ReadBytes and friends will read, it covers the compressed data of compressed strings
+	// and byte arrays too.
+	MaxBytesLength int
+	// MaxDecompressedSize is the largest size a compressed string or byte array may inflate to.
+	MaxDecompressedSize int
+	// MaxCollectionCount is the most elements ReadSlice, ReadMap or Unmarshal will read into one slice or map, a []byte field is a
+	// byte array and falls under MaxBytesLength instead.
+	MaxCollectionCount int
+	// AllocationBudget is how many bytes the Reader may allocate in total over its lifetime, collections count their element size.
+	AllocationBudget int64
+}
+
+// SetOptions sets the limits for everything read from now on, the allocation budget starts over. Called on a sub-reader it gives
+// the sub-reader a budget of its own instead of its parent's.
+func (r *Reader) SetOptions(opts ReaderOptions) {
+	r.opts = opts
+	r.allocated = 0
+	r.budget = nil
+}
+
+// Options returns the limits set with SetOptions.
+func (r *Reader) Options() ReaderOptions {
+	return r.opts
+}
+
+// alloc checks n bytes against max and what's left of the allocation budget, then takes them out of the budget.
+func (r *Reader) alloc(op string, n int64, max int) error {
+	if n < 0 {
+		return r.fail(op, ErrInvalidLength, 0, n, nil)
+	}
+	if max > 0 && n > int64(max) {
+		return r.fail(op, ErrLimitExceeded, int64(max), n, nil)
+	}
+	owner := r
+	if r.budget != nil {
+		owner = r.budget
+	}
+	if owner.opts.AllocationBudget > 0 {
+		if left := owner.opts.AllocationBudget - owner.allocated; n > left {
+			return r.fail(op, ErrLimitExceeded, left, n, nil)
+		}
+		owner.allocated += n
+	}
+	return nil
+}
+
+// allocEach is alloc for count elements of size bytes each. The count comes off the wire and count*size could overflow, a total
+// that doesn't fit in an int64 is more than any budget so it's taken as math.MaxInt64.
+func (r *Reader) allocEach(op string, count int, size uintptr) error {
+	n := int64(math.MaxInt64)
+	if size == 0 || int64(count) <= math.MaxInt64/int64(size) {
+		n = int64(count) * int64(size)
+	}
+	return r.alloc(op, n, 0)
+}
+
+// minLimit returns the tighter of two limits where 0 means no limit.
+func minLimit(a, b int) int {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,310 @@
+package bytestream
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestReader_SetOptions(t *testing.T) {
+	bomb := NewWriter()
+	if err := bomb.WriteCompressedBytes(make([]byte, 1<<20)); err != nil {
+		t.Fatalf("Writer.WriteCompressedBytes() error = %v", err)
+	}
+	compressed := bomb.Buffer.Bytes()
+
+	tests := []struct {
+		name     string
+		data     []byte
+		opts     ReaderOptions
+		read     func(r *Reader) error
+		wantErr  error
+		expected int64
+		actual   int64
+	}{
+		{
+			name:    "string under the limit",
+			data:    []byte{0x00, 0x00, 0x00, 0x02, 'h', 'i'},
+			opts:    ReaderOptions{MaxStringLength: 2},
+			read:    func(r *Reader) error { _, err := r.ReadString(); return err },
+			wantErr: nil,
+		},
+		{
+			name:     "string over the limit",
+			data:     []byte{0x00, 0x00, 0x00, 0x03, 'h', 'e', 'y'},
+			opts:     ReaderOptions{MaxStringLength: 2},
+			read:     func(r *Reader) error { _, err := r.ReadString(); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 2,
+			actual:   3,
+		},
+		{
+			name:     "huge string length",
+			data:     []byte{0x7F, 0xFF, 0xFF, 0xFF},
+			opts:     ReaderOptions{MaxStringLength: 1 << 16},
+			read:     func(r *Reader) error { _, err := r.ReadString(); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 1 << 16,
+			actual:   1<<31 - 1,
+		},
+		{
+			name:     "nullable string over the limit",
+			data:     []byte{0x00, 0x00, 0x00, 0x03, 'h', 'e', 'y'},
+			opts:     ReaderOptions{MaxStringLength: 2},
+			read:     func(r *Reader) error { _, err := r.ReadNullableString(); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 2,
+			actual:   3,
+		},
+		{
+			name:     "bytes over the limit",
+			data:     []byte{0x01, 0x02, 0x03},
+			opts:     ReaderOptions{MaxBytesLength: 2},
+			read:     func(r *Reader) error { _, err := r.ReadBytes(3); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 2,
+			actual:   3,
+		},
+		{
+			name:     "prefixed bytes over the limit",
+			data:     []byte{0x03, 0x01, 0x02, 0x03},
+			opts:     ReaderOptions{MaxBytesLength: 2},
+			read:     func(r *Reader) error { _, err := r.ReadPrefixedBytes(CountByte); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 2,
+			actual:   3,
+		},
+		{
+			name:     "decompressed size over the limit",
+			data:     compressed,
+			opts:     ReaderOptions{MaxDecompressedSize: 1 << 10},
+			read:     func(r *Reader) error { _, err := r.ReadCompressedBytes(); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 1 << 10,
+			actual:   1 << 20,
+		},
+		{
+			name:    "decompressed size under the limit",
+			data:    compressed,
+			opts:    ReaderOptions{MaxDecompressedSize: 1 << 20},
+			read:    func(r *Reader) error { _, err := r.ReadCompressedBytes(); return err },
+			wantErr: nil,
+		},
+		{
+			name:     "compressed size over the limit",
+			data:     compressed,
+			opts:     ReaderOptions{MaxBytesLength: 16},
+			read:     func(r *Reader) error { _, err := r.ReadCompressedString(); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 16,
+			actual:   int64(len(compressed) - 8),
+		},
+		{
+			name:     "slice over the limit",
+			data:     []byte{0x03, 0x01, 0x02, 0x03},
+			opts:     ReaderOptions{MaxCollectionCount: 2},
+			read:     func(r *Reader) error { _, err := ReadSlice(r, CountByte, 0, (*Reader).ReadUInt8); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 2,
+			actual:   3,
+		},
+		{
+			name:     "slice under its own limit but over the reader's",
+			data:     []byte{0x03, 0x01, 0x02, 0x03},
+			opts:     ReaderOptions{MaxCollectionCount: 2},
+			read:     func(r *Reader) error { _, err := ReadSlice(r, CountByte, 10, (*Reader).ReadUInt8); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 2,
+			actual:   3,
+		},
+		{
+			name: "map over the limit",
+			data: []byte{0x03, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
+			opts: ReaderOptions{MaxCollectionCount: 2},
+			read: func(r *Reader) error {
+				_, err := ReadMap(r, CountByte, 0, (*Reader).ReadUInt8, (*Reader).ReadUInt8)
+				return err
+			},
+			wantErr:  ErrLimitExceeded,
+			expected: 2,
+			actual:   3,
+		},
+		{
+			name: "unmarshal over the limit",
+			data: []byte{0x00, 0x00, 0x00, 0x03, 0x00, 0x01, 0x00, 0x02, 0x00, 0x03},
+			opts: ReaderOptions{MaxCollectionCount: 2},
+			read: func(r *Reader) error {
+				var v struct{ Items []uint16 }
+				return r.Unmarshal(&v)
+			},
+			wantErr:  ErrLimitExceeded,
+			expected: 2,
+			actual:   3,
+		},
+		{
+			name: "unmarshal bytes over the collection limit",
+			data: append([]byte{0x00, 0x00, 0x00, 0x64}, make([]byte, 100)...),
+			opts: ReaderOptions{MaxCollectionCount: 10, MaxBytesLength: 1000},
+			read: func(r *Reader) error {
+				var v struct {
+					Data []byte `bs:"bytes"`
+				}
+				return r.Unmarshal(&v)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "unmarshal bytes over MaxBytesLength",
+			data: append([]byte{0x00, 0x00, 0x00, 0x64}, make([]byte, 100)...),
+			opts: ReaderOptions{MaxCollectionCount: 1000, MaxBytesLength: 10},
+			read: func(r *Reader) error {
+				var v struct {
+					Data []byte `bs:"bytes"`
+				}
+				return r.Unmarshal(&v)
+			},
+			wantErr:  ErrLimitExceeded,
+			expected: 10,
+			actual:   100,
+		},
+		{
+			name: "unmarshal budget",
+			data: []byte{0x10, 0x00, 0x00, 0x00},
+			opts: ReaderOptions{AllocationBudget: 1 << 20},
+			read: func(r *Reader) error {
+				var v struct{ Xs []int64 }
+				return r.Unmarshal(&v)
+			},
+			wantErr:  ErrLimitExceeded,
+			expected: 1 << 20,
+			actual:   1 << 31,
+		},
+		{
+			name:     "frame over MaxBytesLength",
+			data:     []byte{0x00, 0x01, 0x00, 0x00, 0x03, 0x00, 0x00, 0x01, 0x02, 0x03},
+			opts:     ReaderOptions{MaxBytesLength: 2},
+			read:     func(r *Reader) error { _, err := r.ReadFrame(MaxFrameLength); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 2,
+			actual:   3,
+		},
+		{
+			name:     "frame budget",
+			data:     []byte{0x00, 0x01, 0x00, 0x00, 0x02, 0x00, 0x00, 0x01, 0x02, 0x00, 0x01, 0x00, 0x00, 0x02, 0x00, 0x00, 0x01, 0x02},
+			opts:     ReaderOptions{AllocationBudget: 3},
+			read:     func(r *Reader) error { r.ReadFrame(MaxFrameLength); _, err := r.ReadFrame(MaxFrameLength); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 1,
+			actual:   2,
+		},
+		{
+			name:     "budget",
+			data:     []byte{0x00, 0x00, 0x00, 0x02, 'h', 'i', 0x00, 0x00, 0x00, 0x02, 'h', 'i'},
+			opts:     ReaderOptions{AllocationBudget: 3},
+			read:     func(r *Reader) error { r.ReadString(); _, err := r.ReadString(); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 1,
+			actual:   2,
+		},
+		{
+			name:     "budget for slice elements",
+			data:     []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02},
+			opts:     ReaderOptions{AllocationBudget: 4},
+			read:     func(r *Reader) error { _, err := ReadSlice(r, CountInt32, 0, readInt32); return err },
+			wantErr:  ErrLimitExceeded,
+			expected: 4,
+			actual:   8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(tt.data)
+			r.SetOptions(tt.opts)
+			err := tt.read(r)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var e *Error
+			if errors.As(err, &e) && (e.Expected != tt.expected || e.Actual != tt.actual) {
+				t.Errorf("error = %v, want expected %v actual %v", err, tt.expected, tt.actual)
+			}
+		})
+	}
+}
+
+func TestReader_AllocOverflow(t *testing.T) {
+	// Counts whose size in bytes wraps around an int64, to 0 or to a negative number
+	tests := []struct {
+		name string
+		read func(r *Reader) error
+	}{
+		{
+			name: "slice",
+			read: func(r *Reader) error { _, err := ReadSlice(r, CountUVarInt, 0, readInt32); return err },
+		},
+		{
+			name: "unmarshal",
+			read: func(r *Reader) error {
+				var v struct {
+					Xs []int64 `bs:",len=uvarint"`
+				}
+				return r.Unmarshal(&v)
+			},
+		},
+	}
+	for _, tt := range tests {
+		for _, count := range []uint64{1 << 62, 1<<61 + 1<<60} {
+			r := NewReader(AppendUVarInt(nil, count))
+			r.SetOptions(ReaderOptions{AllocationBudget: 1 << 20})
+			err := tt.read(r)
+			var e *Error
+			if !errors.As(err, &e) || e.Kind != ErrLimitExceeded || e.Expected != 1<<20 || e.Actual != math.MaxInt64 {
+				t.Errorf("%s of %v elements error = %v, want %v", tt.name, count, err, ErrLimitExceeded)
+			}
+			if r.allocated != 0 {
+				t.Errorf("%s of %v elements allocated %v, want 0", tt.name, count, r.allocated)
+			}
+		}
+	}
+}
+
+func TestReader_SetOptions_Stream(t *testing.T) {
+	// A Reader backed by an io.Reader must not try to buffer a length it has already rejected
+	src := &countingReader{r: bytes.NewReader(append([]byte{0x7F, 0xFF, 0xFF, 0xFF}, make([]byte, 1<<16)...))}
+	r := NewReaderFromSize(src, 4)
+	r.SetOptions(ReaderOptions{MaxStringLength: 1 << 10})
+	if _, err := r.ReadString(); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("Reader.ReadString() error = %v, want %v", err, ErrLimitExceeded)
+	}
+	if src.n > 4 {
+		t.Errorf("Reader.ReadString() read %v bytes from its source, want 4", src.n)
+	}
+}
+
+func TestReader_ReadCompressedBytes_Bomb(t *testing.T) {
+	// The header claims 16 bytes, the data inflates to a megabyte
+	w := NewWriter()
+	if err := w.WriteCompressedBytes(make([]byte, 1<<20)); err != nil {
+		t.Fatalf("Writer.WriteCompressedBytes() error = %v", err)
+	}
+	data := w.Buffer.Bytes()
+	copy(data[4:8], []byte{0x10, 0x00, 0x00, 0x00})
+
+	_, err := NewReader(data).ReadCompressedBytes()
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != ErrDecompress || e.Expected != 16 || e.Actual != 17 {
+		t.Errorf("Reader.ReadCompressedBytes() error = %v, want %v after 17 bytes", err, ErrDecompress)
+	}
+}
+
+type countingReader struct {
+	r *bytes.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
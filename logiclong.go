@@ -0,0 +1,86 @@
+package bytestream
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TagChars is the alphabet player/clan tags are written in, a tag is just the (low << 8 | high) id in base 14 with these digits.
+const TagChars = "0289PYLQGRJCUV"
+
+// The id packed in a tag can't be bigger than a full low int32 shifted past the high byte.
+const maxTagID = (1<<31-1)<<8 | 0xFF
+
+// A LogicLong is the game's 64-bit id, it's sent as two int32s (high first) and is what a hashtag decodes into.
+type LogicLong struct {
+	High int32
+	Low  int32
+}
+
+// ParseTag decodes a hashtag like "#2PP" into a LogicLong.
+func ParseTag(tag string) (LogicLong, error) {
+	high, low, err := TagToID(tag)
+	if err != nil {
+		return LogicLong{}, err
+	}
+	return LogicLong{High: high, Low: low}, nil
+}
+
+// Tag returns the hashtag for l, ids that have no tag (negative low or a high outside 0-255) return an error.
+func (l LogicLong) Tag() (string, error) {
+	return IDToTag(l.High, l.Low)
+}
+
+// String prints l as a hashtag when it is a valid tag, otherwise it falls back to "(high, low)".
+func (l LogicLong) String() string {
+	tag, err := l.Tag()
+	if err != nil {
+		return fmt.Sprintf("(%d, %d)", l.High, l.Low)
+	}
+	return tag
+}
+
+// TagToID converts a hashtag into its high and low ids, the leading # is optional and lowercase letters are accepted.
+func TagToID(tag string) (int32, int32, error) {
+	tag = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(tag)), "#")
+	if tag == "" {
+		return 0, 0, fmt.Errorf("%w: empty", ErrInvalidTag)
+	}
+
+	var id int64
+	for _, c := range tag {
+		digit := strings.IndexRune(TagChars, c)
+		if digit == -1 {
+			return 0, 0, fmt.Errorf("%w: bad character %q", ErrInvalidTag, c)
+		}
+		id = id*int64(len(TagChars)) + int64(digit)
+		if id > maxTagID {
+			return 0, 0, fmt.Errorf("%w: %s is too long", ErrInvalidTag, tag)
+		}
+	}
+	return int32(id & 0xFF), int32(id >> 8), nil
+}
+
+// IDToTag converts a high and low id into a hashtag.
+func IDToTag(high, low int32) (string, error) {
+	if high < 0 || high > 0xFF {
+		return "", fmt.Errorf("%w: high id %d", ErrInvalidTag, high)
+	}
+	if low < 0 {
+		return "", fmt.Errorf("%w: low id %d", ErrInvalidTag, low)
+	}
+
+	id := int64(low)<<8 | int64(high)
+	if id == 0 {
+		return "#" + TagChars[:1], nil
+	}
+	var tag []byte
+	for id > 0 {
+		tag = append(tag, TagChars[id%int64(len(TagChars))])
+		id /= int64(len(TagChars))
+	}
+	for i, j := 0, len(tag)-1; i < j; i, j = i+1, j-1 {
+		tag[i], tag[j] = tag[j], tag[i]
+	}
+	return "#" + string(tag), nil
+}
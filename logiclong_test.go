@@ -0,0 +1,119 @@
+package bytestream
+
+import (
+	"testing"
+)
+
+func TestTagToID(t *testing.T) {
+	type args struct {
+		tag string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantHigh int32
+		wantLow  int32
+		wantErr  bool
+	}{
+		{name: "empty", args: args{tag: ""}, wantErr: true},
+		{name: "hash only", args: args{tag: "#"}, wantErr: true},
+		{name: "zero", args: args{tag: "#0"}, wantHigh: 0, wantLow: 0, wantErr: false},
+		{name: "single", args: args{tag: "#V"}, wantHigh: 13, wantLow: 0, wantErr: false},
+		{name: "short", args: args{tag: "#2PP"}, wantHigh: 0, wantLow: 1, wantErr: false},
+		{name: "player", args: args{tag: "#8L9CQ0C"}, wantHigh: 63, wantLow: 72003, wantErr: false},
+		{name: "long", args: args{tag: "#YVJ2LRGP"}, wantHigh: 56, wantLow: 2462447, wantErr: false},
+		{name: "no hash", args: args{tag: "YVJ2LRGP"}, wantHigh: 56, wantLow: 2462447, wantErr: false},
+		{name: "lowercase", args: args{tag: "#yvj2lrgp"}, wantHigh: 56, wantLow: 2462447, wantErr: false},
+		{name: "bad character", args: args{tag: "#2PA"}, wantErr: true},
+		{name: "double hash", args: args{tag: "##2PP"}, wantErr: true},
+		{name: "too long", args: args{tag: "#VVVVVVVVVVVV"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotHigh, gotLow, err := TagToID(tt.args.tag)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TagToID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotHigh != tt.wantHigh || gotLow != tt.wantLow {
+				t.Errorf("TagToID() = (%v, %v), want (%v, %v)", gotHigh, gotLow, tt.wantHigh, tt.wantLow)
+			}
+		})
+	}
+}
+
+func TestIDToTag(t *testing.T) {
+	type args struct {
+		high int32
+		low  int32
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{name: "zero", args: args{high: 0, low: 0}, want: "#0", wantErr: false},
+		{name: "short", args: args{high: 0, low: 1}, want: "#2PP", wantErr: false},
+		{name: "player", args: args{high: 63, low: 72003}, want: "#8L9CQ0C", wantErr: false},
+		{name: "long", args: args{high: 56, low: 2462447}, want: "#YVJ2LRGP", wantErr: false},
+		{name: "negative high", args: args{high: -1, low: 1}, wantErr: true},
+		{name: "high too big", args: args{high: 256, low: 1}, wantErr: true},
+		{name: "negative low", args: args{high: 1, low: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IDToTag(tt.args.high, tt.args.low)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IDToTag() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IDToTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogicLong_RoundTrip(t *testing.T) {
+	tests := []LogicLong{
+		{High: 0, Low: 0},
+		{High: 0, Low: 1},
+		{High: 255, Low: 0},
+		{High: 63, Low: 72003},
+		{High: 255, Low: 2147483647},
+	}
+	for _, tt := range tests {
+		t.Run(tt.String(), func(t *testing.T) {
+			tag, err := tt.Tag()
+			if err != nil {
+				t.Fatalf("LogicLong.Tag() error = %v", err)
+			}
+			got, err := ParseTag(tag)
+			if err != nil {
+				t.Fatalf("ParseTag(%s) error = %v", tag, err)
+			}
+			if got != tt {
+				t.Errorf("ParseTag(%s) = %v, want %v", tag, got, tt)
+			}
+		})
+	}
+}
+
+func TestLogicLong_String(t *testing.T) {
+	tests := []struct {
+		name string
+		l    LogicLong
+		want string
+	}{
+		{name: "tag", l: LogicLong{High: 63, Low: 72003}, want: "#8L9CQ0C"},
+		{name: "not a tag", l: LogicLong{High: 1000, Low: -5}, want: "(1000, -5)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.String(); got != tt.want {
+				t.Errorf("LogicLong.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
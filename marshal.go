@@ -0,0 +1,690 @@
+package bytestream
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Marshal packs the exported fields of the struct v in order, the `bs` struct tag picks the primitive used for each field.
+//
+// A tag looks like `bs:"kind,option,..."`. The kind is one of bool, boolean (bit-packed like ReadBoolean), int8 through int64 or uint8
+// through uint64 (any multiple of 8 bits, so int24/uint40/... work too), byte, long, ulong, varint, uvarint, rrsint32, float32,
+// float64, string, compressed, bytes, logiclong or rrslong. When the kind is left out it is picked from the field's type. The options are le/be for the
+// byte order (big endian by default) and len=kind for the length prefix of slices and byte arrays (int32 by default), the kind of a
+// slice applies to its elements. Use `bs:"-"` to skip a field.
+//
+// A field without a kind whose type implements Encodable/Decodable is written with WriteValue and read with ReadValue, failing that
+// encoding.BinaryMarshaler/BinaryUnmarshaler are used through WriteBinary and ReadBinary.
+func Marshal(v any) ([]byte, error) {
+	w := NewWriter()
+	if err := w.Marshal(v); err != nil {
+		return nil, err
+	}
+	return w.Buffer.Bytes(), nil
+}
+
+// Unmarshal is the inverse of Marshal, v has to be a pointer to a struct.
+func Unmarshal(data []byte, v any) error {
+	return NewReader(data).Unmarshal(v)
+}
+
+// Marshal writes the struct v the same way the package level Marshal does.
+func (w *Writer) Marshal(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot marshal %T, expected a struct", v)
+	}
+	return encodeStruct(w, rv, "")
+}
+
+// Unmarshal reads into the struct pointed to by v the same way the package level Unmarshal does.
+func (r *Reader) Unmarshal(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unmarshal into %T, expected a non-nil pointer to a struct", v)
+	}
+	return decodeStruct(r, rv.Elem(), "")
+}
+
+// A FieldError is returned by Marshal and Unmarshal, Field is the path to the field that failed (e.g. Clan.Members[3].Name).
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return "field " + e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+type fieldTag struct {
+	kind       string
+	endianness Endianness
+	length     string
+}
+
+func parseTag(tag string) (fieldTag, error) {
+	ft := fieldTag{endianness: BigEndian, length: "int32"}
+	for i, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+		case part == "le":
+			ft.endianness = LittleEndian
+		case part == "be":
+			ft.endianness = BigEndian
+		case strings.HasPrefix(part, "len="):
+			ft.length = strings.TrimPrefix(part, "len=")
+			if _, _, ok := intKind(ft.length); !ok && ft.length != "varint" && ft.length != "uvarint" && ft.length != "rrsint32" {
+				return ft, fmt.Errorf("invalid length prefix %q", ft.length)
+			}
+		case i == 0:
+			ft.kind = part
+		default:
+			return ft, fmt.Errorf("unknown tag option %q", part)
+		}
+	}
+	return ft, nil
+}
+
+// intKind parses kinds like int24 or uint40 into their size in bytes and sign.
+func intKind(kind string) (uint8, Sign, bool) {
+	sign := Signed
+	bits := strings.TrimPrefix(kind, "int")
+	if strings.HasPrefix(kind, "uint") {
+		sign = Unsigned
+		bits = strings.TrimPrefix(kind, "uint")
+	} else if kind == "byte" {
+		return ByteSize, Unsigned, true
+	}
+	if bits == kind {
+		return 0, sign, false
+	}
+	n, err := strconv.Atoi(bits)
+	if err != nil || n < 8 || n > 64 || n%8 != 0 {
+		return 0, sign, false
+	}
+	return uint8(n / 8), sign, true
+}
+
+var logicLongType = reflect.TypeOf(LogicLong{})
+
+// defaultKind picks the kind for a field that doesn't set one in its tag.
+func defaultKind(t reflect.Type) string {
+	if t == logicLongType {
+		return "logiclong"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int" + strconv.Itoa(t.Bits())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint" + strconv.Itoa(t.Bits())
+	case reflect.Float32, reflect.Float64:
+		return "float" + strconv.Itoa(t.Bits())
+	case reflect.Int:
+		return "long"
+	case reflect.Uint:
+		return "ulong"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "bytes"
+		}
+	}
+	return ""
+}
+
+func joinField(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func encodeStruct(w *Writer, v reflect.Value, path string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("bs")
+		if !field.IsExported() || (ok && tag == "-") {
+			continue
+		}
+		name := joinField(path, field.Name)
+		ft, err := parseTag(tag)
+		if err != nil {
+			return &FieldError{Field: name, Err: err}
+		}
+		if err := encodeValue(w, v.Field(i), ft, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeStruct(r *Reader, v reflect.Value, path string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("bs")
+		if !field.IsExported() || (ok && tag == "-") {
+			continue
+		}
+		name := joinField(path, field.Name)
+		ft, err := parseTag(tag)
+		if err != nil {
+			return &FieldError{Field: name, Err: err}
+		}
+		if err := decodeValue(r, v.Field(i), ft, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeValue(w *Writer, v reflect.Value, ft fieldTag, path string) error {
+	kind := ft.kind
+	if kind == "" {
+		if handled, err := encodeCustom(w, v); handled {
+			if err != nil {
+				return &FieldError{Field: path, Err: err}
+			}
+			return nil
+		}
+		kind = defaultKind(v.Type())
+	}
+
+	switch {
+	case v.Kind() == reflect.Struct && kind == "":
+		return encodeStruct(w, v, path)
+	case v.Kind() == reflect.Slice:
+		if kind == "bytes" && v.Type().Elem().Kind() != reflect.Uint8 {
+			return &FieldError{Field: path, Err: fmt.Errorf("kind bytes needs a []byte, got %s", v.Type())}
+		}
+		if err := writeLength(w, v.Len(), ft); err != nil {
+			return &FieldError{Field: path, Err: err}
+		}
+		if kind == "bytes" {
+			if err := w.WriteBytes(v.Bytes()); err != nil {
+				return &FieldError{Field: path, Err: err}
+			}
+			return nil
+		}
+		return encodeElements(w, v, ft, path)
+	case v.Kind() == reflect.Array:
+		return encodeElements(w, v, ft, path)
+	}
+
+	if err := encodePrimitive(w, v, kind, ft.endianness); err != nil {
+		return &FieldError{Field: path, Err: err}
+	}
+	return nil
+}
+
+func encodeElements(w *Writer, v reflect.Value, ft fieldTag, path string) error {
+	elem := fieldTag{kind: ft.kind, endianness: ft.endianness, length: "int32"}
+	for i := 0; i < v.Len(); i++ {
+		if err := encodeValue(w, v.Index(i), elem, path+"["+strconv.Itoa(i)+"]"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeValue(r *Reader, v reflect.Value, ft fieldTag, path string) error {
+	kind := ft.kind
+	if kind == "" {
+		if handled, err := decodeCustom(r, v); handled {
+			if err != nil {
+				return &FieldError{Field: path, Err: err}
+			}
+			return nil
+		}
+		kind = defaultKind(v.Type())
+	}
+
+	switch {
+	case v.Kind() == reflect.Struct && kind == "":
+		return decodeStruct(r, v, path)
+	case v.Kind() == reflect.Slice:
+		if kind == "bytes" && v.Type().Elem().Kind() != reflect.Uint8 {
+			return &FieldError{Field: path, Err: fmt.Errorf("kind bytes needs a []byte, got %s", v.Type())}
+		}
+		// A byte array's length is checked against MaxBytesLength by ReadBytes, it isn't a collection
+		max := r.opts.MaxCollectionCount
+		if kind == "bytes" {
+			max = 0
+		}
+		length, err := r.readLength("Unmarshal", ft.lengthPrefix(), max)
+		if err != nil {
+			return &FieldError{Field: path, Err: err}
+		}
+		if length < 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if kind == "bytes" {
+			_bytes, err := r.ReadBytes(length)
+			if err != nil {
+				return &FieldError{Field: path, Err: err}
+			}
+			v.SetBytes(_bytes)
+			return nil
+		}
+		return decodeSlice(r, v, length, ft, path)
+	case v.Kind() == reflect.Array:
+		return decodeElements(r, v, ft, path)
+	}
+
+	if err := decodePrimitive(r, v, kind, ft.endianness); err != nil {
+		return &FieldError{Field: path, Err: err}
+	}
+	return nil
+}
+
+// decodeSlice decodes length elements into a new slice, the count comes off the wire so it's charged against the allocation budget
+// and the slice grows as elements actually arrive instead of being allocated up front.
+func decodeSlice(r *Reader, v reflect.Value, length int, ft fieldTag, path string) error {
+	elemType := v.Type().Elem()
+	if err := r.allocEach("Unmarshal", length, elemType.Size()); err != nil {
+		return &FieldError{Field: path, Err: err}
+	}
+	s := reflect.MakeSlice(v.Type(), 0, minInt(length, r.Remaining()))
+	elem := fieldTag{kind: ft.kind, endianness: ft.endianness, length: "int32"}
+	for i := 0; i < length; i++ {
+		s = reflect.Append(s, reflect.Zero(elemType))
+		if err := decodeValue(r, s.Index(i), elem, path+"["+strconv.Itoa(i)+"]"); err != nil {
+			return err
+		}
+	}
+	v.Set(s)
+	return nil
+}
+
+func decodeElements(r *Reader, v reflect.Value, ft fieldTag, path string) error {
+	elem := fieldTag{kind: ft.kind, endianness: ft.endianness, length: "int32"}
+	for i := 0; i < v.Len(); i++ {
+		if err := decodeValue(r, v.Index(i), elem, path+"["+strconv.Itoa(i)+"]"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	encodableType         = reflect.TypeOf((*Encodable)(nil)).Elem()
+	decodableType         = reflect.TypeOf((*Decodable)(nil)).Elem()
+	binaryMarshalerType   = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+)
+
+// encodeCustom writes v with WriteValue or WriteBinary if its type (or a pointer to it) implements Encodable or
+// encoding.BinaryMarshaler, handled is false when it implements neither.
+func encodeCustom(w *Writer, v reflect.Value) (handled bool, err error) {
+	target, handled := methodValue(v, encodableType)
+	if !handled {
+		if target, handled = methodValue(v, binaryMarshalerType); !handled {
+			return false, nil
+		}
+	}
+	if target.Kind() == reflect.Pointer && target.IsNil() {
+		return true, fmt.Errorf("cannot encode a nil %s", v.Type())
+	}
+	if e, ok := target.Interface().(Encodable); ok {
+		return true, w.WriteValue(e)
+	}
+	return true, w.WriteBinary(target.Interface().(encoding.BinaryMarshaler))
+}
+
+// decodeCustom is the counterpart of encodeCustom, a nil pointer field is allocated before decoding into it.
+func decodeCustom(r *Reader, v reflect.Value) (handled bool, err error) {
+	target, handled := methodValue(v, decodableType)
+	if !handled {
+		if target, handled = methodValue(v, binaryUnmarshalerType); !handled {
+			return false, nil
+		}
+	}
+	if target.Kind() == reflect.Pointer && target.IsNil() {
+		v.Set(reflect.New(v.Type().Elem()))
+		target = v
+	}
+	if d, ok := target.Interface().(Decodable); ok {
+		return true, r.ReadValue(d)
+	}
+	return true, r.ReadBinary(target.Interface().(encoding.BinaryUnmarshaler))
+}
+
+// methodValue returns v or a pointer to it, whichever implements iface.
+func methodValue(v reflect.Value, iface reflect.Type) (reflect.Value, bool) {
+	if v.Type().Implements(iface) && (v.Kind() == reflect.Pointer || iface == encodableType || iface == binaryMarshalerType) {
+		return v, true
+	}
+	if reflect.PointerTo(v.Type()).Implements(iface) {
+		if v.CanAddr() {
+			return v.Addr(), true
+		}
+		// Marshal was handed a struct by value, encode a copy
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return p, true
+	}
+	return reflect.Value{}, false
+}
+
+// lengthPrefix turns the len= kind of the tag into a LengthPrefix, a signed count of -1 means the slice is nil.
+func (ft fieldTag) lengthPrefix() LengthPrefix {
+	switch ft.length {
+	case "varint":
+		return LengthPrefix{Encoding: LengthVarInt, Sign: Signed, Nullable: true, Null: -1}
+	case "uvarint":
+		return LengthPrefix{Encoding: LengthVarInt, Sign: Unsigned}
+	case "rrsint32":
+		return CountVInt
+	}
+	size, sign, _ := intKind(ft.length)
+	return LengthPrefix{Encoding: LengthFixed, Size: size, Endianness: ft.endianness, Sign: sign, Nullable: sign == Signed, Null: -1}
+}
+
+// writeLength writes the element count of a slice using the len= kind of the tag.
+func writeLength(w *Writer, length int, ft fieldTag) error {
+	return w.writeLength("Marshal", ft.lengthPrefix(), length, false)
+}
+
+// signedValue and unsignedValue let a field of any integer type be written with any integer kind, the writers do the range checks.
+func signedValue(v reflect.Value) (int64, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > 1<<63-1 {
+			return 0, fmt.Errorf("value %d overflows int64", v.Uint())
+		}
+		return int64(v.Uint()), nil
+	}
+	return 0, fmt.Errorf("cannot write %s as an integer", v.Type())
+}
+
+func unsignedValue(v reflect.Value) (uint64, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < 0 {
+			return 0, fmt.Errorf("cannot write negative value %d as an unsigned integer", v.Int())
+		}
+		return uint64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), nil
+	}
+	return 0, fmt.Errorf("cannot write %s as an unsigned integer", v.Type())
+}
+
+func setSigned(v reflect.Value, data int64) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.OverflowInt(data) {
+			return fmt.Errorf("value %d overflows %s", data, v.Type())
+		}
+		v.SetInt(data)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if data < 0 || v.OverflowUint(uint64(data)) {
+			return fmt.Errorf("value %d overflows %s", data, v.Type())
+		}
+		v.SetUint(uint64(data))
+		return nil
+	}
+	return fmt.Errorf("cannot read an integer into %s", v.Type())
+}
+
+func setUnsigned(v reflect.Value, data uint64) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if data > 1<<63-1 || v.OverflowInt(int64(data)) {
+			return fmt.Errorf("value %d overflows %s", data, v.Type())
+		}
+		v.SetInt(int64(data))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.OverflowUint(data) {
+			return fmt.Errorf("value %d overflows %s", data, v.Type())
+		}
+		v.SetUint(data)
+		return nil
+	}
+	return fmt.Errorf("cannot read an integer into %s", v.Type())
+}
+
+func encodePrimitive(w *Writer, v reflect.Value, kind string, endianness Endianness) error {
+	if size, sign, ok := intKind(kind); ok {
+		if sign == Signed {
+			data, err := signedValue(v)
+			if err != nil {
+				return err
+			}
+			return w.WriteIntSize(data, size, endianness)
+		}
+		data, err := unsignedValue(v)
+		if err != nil {
+			return err
+		}
+		return w.WriteUIntSize(int64(data), size, endianness)
+	}
+
+	switch kind {
+	case "bool":
+		if v.Kind() != reflect.Bool {
+			return fmt.Errorf("kind bool needs a bool, got %s", v.Type())
+		}
+		return w.WriteBool(v.Bool(), 1)
+	case "boolean":
+		if v.Kind() != reflect.Bool {
+			return fmt.Errorf("kind boolean needs a bool, got %s", v.Type())
+		}
+		return w.WriteBoolean(v.Bool())
+	case "long":
+		data, err := signedValue(v)
+		if err != nil {
+			return err
+		}
+		return w.WriteLong(data, endianness)
+	case "ulong":
+		data, err := unsignedValue(v)
+		if err != nil {
+			return err
+		}
+		return w.WriteUnsignedLong(data, endianness)
+	case "varint":
+		data, err := signedValue(v)
+		if err != nil {
+			return err
+		}
+		return w.WriteVarInt(data)
+	case "uvarint":
+		data, err := unsignedValue(v)
+		if err != nil {
+			return err
+		}
+		return w.WriteUVarInt(data)
+	case "rrsint32":
+		data, err := signedValue(v)
+		if err != nil {
+			return err
+		}
+		if data < -1<<31 || data > 1<<31-1 {
+			return fmt.Errorf("rrsint32 overflow")
+		}
+		return w.WriteRRSInt32(int32(data))
+	case "float32", "float64":
+		if !v.CanFloat() {
+			return fmt.Errorf("kind %s needs a float, got %s", kind, v.Type())
+		}
+		if kind == "float32" {
+			return w.WriteFloat32(float32(v.Float()), endianness)
+		}
+		return w.WriteFloat64(v.Float(), endianness)
+	case "string", "compressed":
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("kind %s needs a string, got %s", kind, v.Type())
+		}
+		if kind == "compressed" {
+			return w.WriteCompressedString(v.String())
+		}
+		return w.WriteString(v.String())
+	case "logiclong":
+		if v.Type() != logicLongType {
+			return fmt.Errorf("kind logiclong needs a LogicLong, got %s", v.Type())
+		}
+		return w.WriteLogicLong(v.Interface().(LogicLong), endianness)
+	case "rrslong":
+		if v.Type() != logicLongType {
+			return fmt.Errorf("kind rrslong needs a LogicLong, got %s", v.Type())
+		}
+		return w.WriteRRSLong(v.Interface().(LogicLong))
+	case "bytes":
+		return fmt.Errorf("kind bytes needs a []byte, got %s", v.Type())
+	case "":
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return fmt.Errorf("unknown kind %q", kind)
+}
+
+func decodePrimitive(r *Reader, v reflect.Value, kind string, endianness Endianness) error {
+	if size, sign, ok := intKind(kind); ok {
+		if sign == Signed {
+			data, err := r.ReadIntSize(size, endianness)
+			if err != nil {
+				return err
+			}
+			return setSigned(v, data)
+		}
+		data, err := r.ReadUIntSize(size, endianness)
+		if err != nil {
+			return err
+		}
+		return setUnsigned(v, uint64(data))
+	}
+
+	switch kind {
+	case "bool":
+		if v.Kind() != reflect.Bool {
+			return fmt.Errorf("kind bool needs a bool, got %s", v.Type())
+		}
+		data, _, err := r.ReadBool()
+		if err != nil {
+			return err
+		}
+		v.SetBool(data)
+		return nil
+	case "boolean":
+		if v.Kind() != reflect.Bool {
+			return fmt.Errorf("kind boolean needs a bool, got %s", v.Type())
+		}
+		data, err := r.ReadBoolean()
+		if err != nil {
+			return err
+		}
+		v.SetBool(data)
+		return nil
+	case "long":
+		data, err := r.ReadLong(endianness)
+		if err != nil {
+			return err
+		}
+		return setSigned(v, int64(data))
+	case "ulong":
+		data, err := r.ReadUnsignedLong(endianness)
+		if err != nil {
+			return err
+		}
+		return setUnsigned(v, uint64(data))
+	case "varint":
+		data, err := r.ReadVarInt()
+		if err != nil {
+			return err
+		}
+		return setSigned(v, data)
+	case "uvarint":
+		data, err := r.ReadUVarInt()
+		if err != nil {
+			return err
+		}
+		return setUnsigned(v, data)
+	case "rrsint32":
+		data, err := r.ReadRRSInt32()
+		if err != nil {
+			return err
+		}
+		return setSigned(v, int64(data))
+	case "float32", "float64":
+		if !v.CanFloat() {
+			return fmt.Errorf("kind %s needs a float, got %s", kind, v.Type())
+		}
+		var data float64
+		if kind == "float32" {
+			f, err := r.ReadFloat32(endianness)
+			if err != nil {
+				return err
+			}
+			data = float64(f)
+		} else {
+			f, err := r.ReadFloat64(endianness)
+			if err != nil {
+				return err
+			}
+			data = f
+		}
+		v.SetFloat(data)
+		return nil
+	case "string", "compressed":
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("kind %s needs a string, got %s", kind, v.Type())
+		}
+		var data string
+		var err error
+		if kind == "compressed" {
+			data, err = r.ReadCompressedString()
+		} else {
+			data, err = r.ReadString()
+		}
+		if err != nil {
+			return err
+		}
+		v.SetString(data)
+		return nil
+	case "logiclong":
+		if v.Type() != logicLongType {
+			return fmt.Errorf("kind logiclong needs a LogicLong, got %s", v.Type())
+		}
+		data, err := r.ReadLogicLong(endianness)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(data))
+		return nil
+	case "rrslong":
+		if v.Type() != logicLongType {
+			return fmt.Errorf("kind rrslong needs a LogicLong, got %s", v.Type())
+		}
+		data, err := r.ReadRRSLong()
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(data))
+		return nil
+	case "bytes":
+		return fmt.Errorf("kind bytes needs a []byte, got %s", v.Type())
+	case "":
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return fmt.Errorf("unknown kind %q", kind)
+}
@@ -0,0 +1,174 @@
+package bytestream
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type marshalItem struct {
+	ID    int32 `bs:"int24,le"`
+	Count uint8
+}
+
+type marshalMessage struct {
+	Version  uint16
+	Flag     bool
+	Big      int64 `bs:"int40"`
+	Score    int32 `bs:"varint"`
+	Name     string
+	Motd     string `bs:"compressed"`
+	Player   LogicLong
+	Payload  []byte `bs:",len=uint8"`
+	Items    []marshalItem
+	Levels   []int32   `bs:"int16,len=uvarint"`
+	Position [2]int16  `bs:",le"`
+	Trophies int32     `bs:"rrsint32"`
+	Clan     LogicLong `bs:"rrslong"`
+	Badges   []int32   `bs:"rrsint32,len=rrsint32"`
+	Speed    float32   `bs:",le"`
+	Ratio    float64   `bs:"float64"`
+	Skipped  int32     `bs:"-"`
+	internal int32
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	in := marshalMessage{
+		Version:  3,
+		Flag:     true,
+		Big:      -549755813888,
+		Score:    -300,
+		Name:     "hello there!",
+		Motd:     "welcome back",
+		Player:   LogicLong{High: 63, Low: 72003},
+		Payload:  []byte{0x01, 0x02, 0x03},
+		Items:    []marshalItem{{ID: -1, Count: 2}, {ID: 8388607, Count: 255}},
+		Levels:   []int32{1, -2, 32767},
+		Position: [2]int16{-5, 5},
+		Trophies: -8193,
+		Clan:     LogicLong{High: 12, Low: 345678},
+		Badges:   []int32{-1, 64},
+		Speed:    -1.5,
+		Ratio:    0.1,
+		Skipped:  99,
+		internal: 42,
+	}
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var out marshalMessage
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	in.Skipped, in.internal = 0, 0
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("Unmarshal() = %+v, want %+v", out, in)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	type nested struct {
+		A uint8
+	}
+	tests := []struct {
+		name    string
+		v       any
+		want    []byte
+		wantErr bool
+	}{
+		{name: "ints", v: struct {
+			A int16
+			B uint32 `bs:"uint24,le"`
+		}{A: -2, B: 0x010203}, want: []byte{0xFF, 0xFE, 0x03, 0x02, 0x01}},
+		{name: "booleans", v: struct {
+			A bool `bs:"boolean"`
+			B bool `bs:"boolean"`
+			C bool `bs:"boolean"`
+			D int8
+			E bool `bs:"boolean"`
+		}{A: true, C: true, D: 1, E: true}, want: []byte{0x05, 0x01, 0x01}},
+		{name: "pointer", v: &struct{ A int8 }{A: -1}, want: []byte{0xFF}},
+		{name: "string", v: struct{ S string }{S: "ab"}, want: []byte{0x00, 0x00, 0x00, 0x02, 'a', 'b'}},
+		{name: "nested", v: struct{ N []nested }{N: []nested{{A: 1}, {A: 2}}}, want: []byte{0x00, 0x00, 0x00, 0x02, 0x01, 0x02}},
+		{name: "byte length", v: struct {
+			B []byte `bs:"bytes,len=int8"`
+		}{B: []byte{0xAA}}, want: []byte{0x01, 0xAA}},
+		{name: "not a struct", v: 5, wantErr: true},
+		{name: "overflow", v: struct {
+			A int32 `bs:"int8"`
+		}{A: 200}, wantErr: true},
+		{name: "negative unsigned", v: struct {
+			A int32 `bs:"uint16"`
+		}{A: -1}, wantErr: true},
+		{name: "unknown kind", v: struct {
+			A int32 `bs:"int12"`
+		}{A: 1}, wantErr: true},
+		{name: "unknown option", v: struct {
+			A int32 `bs:"int32,middle"`
+		}{A: 1}, wantErr: true},
+		{name: "floats", v: struct {
+			A float32
+			B float64 `bs:"float32,le"`
+		}{A: 1, B: -2}, want: []byte{0x3F, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0xC0}},
+		{name: "float kind on an int", v: struct {
+			A int32 `bs:"float32"`
+		}{A: 1}, wantErr: true},
+		{name: "unsupported", v: struct{ C complex128 }{C: 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Marshal() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Marshal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_FieldError(t *testing.T) {
+	type inner struct {
+		Name string
+	}
+	type outer struct {
+		ID    int32
+		Inner []inner
+	}
+	data := []byte{
+		0x00, 0x00, 0x00, 0x01, // ID
+		0x00, 0x00, 0x00, 0x02, // len(Inner)
+		0x00, 0x00, 0x00, 0x01, 'a', // Inner[0].Name
+		0x00, 0x00, 0x00, 0x05, 'b', // Inner[1].Name is cut short
+	}
+	var out outer
+	err := Unmarshal(data, &out)
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("Unmarshal() error = %v, want a *FieldError", err)
+	}
+	if fieldErr.Field != "Inner[1].Name" {
+		t.Errorf("FieldError.Field = %v, want %v", fieldErr.Field, "Inner[1].Name")
+	}
+
+	if err := Unmarshal(data, out); err == nil {
+		t.Errorf("Unmarshal() into a non-pointer should fail")
+	}
+}
+
+func TestUnmarshal_NilSlice(t *testing.T) {
+	var out struct {
+		Items []int32
+	}
+	if err := Unmarshal([]byte{0xFF, 0xFF, 0xFF, 0xFF}, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out.Items != nil {
+		t.Errorf("Unmarshal() = %v, want nil", out.Items)
+	}
+}
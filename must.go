@@ -0,0 +1,692 @@
+package bytestream
+
+import (
+	"encoding"
+	"encoding/binary"
+)
+
+// A MustReader is a Reader with a sticky error, for decoders that read a lot of fields in a row. The first failure is kept and every
+// call after it does nothing and returns zero values, so the error only has to be checked once at the end with Err:
+//
+//	m := r.Must()
+//	id := m.Int32()
+//	name := m.Str()
+//	if err := m.Err(); err != nil {
+//		return err
+//	}
+//
+// The methods are the Reader's Read* methods without the Read prefix and the error (ReadString is Str). Those that take an Endianness
+// are the Reader's short methods instead (Int16, Int32...), which read with the Reader's byte order, SetOrder changes it between
+// calls. The error is an *Error, so it says which call failed and at what offset.
+type MustReader Reader
+
+// Must returns a MustReader reading from the same stream as r, it keeps its error in r so r.Must() can be called again and again.
+func (r *Reader) Must() *MustReader {
+	return (*MustReader)(r)
+}
+
+// Err returns the first error a MustReader call ran into, or nil.
+func (m *MustReader) Err() error {
+	return m.mustErr
+}
+
+func (m *MustReader) Offset() int64 {
+	return m.reader().Offset()
+}
+
+func (m *MustReader) Remaining() int {
+	return m.reader().Remaining()
+}
+
+// SetOrder sets the byte order the short methods read with from now on, it's the Reader's own so the Reader reads with it too.
+func (m *MustReader) SetOrder(order binary.ByteOrder) {
+	m.reader().SetOrder(order)
+}
+
+// Order returns the byte order the short methods read with.
+func (m *MustReader) Order() Endianness {
+	return m.reader().Order()
+}
+
+func (m *MustReader) reader() *Reader {
+	return (*Reader)(m)
+}
+
+func (m *MustReader) Byte() (data byte) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadByte()
+	}
+	return
+}
+
+func (m *MustReader) Bytes(length int) (data []byte) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadBytes(length)
+	}
+	return
+}
+
+func (m *MustReader) Bool() (data bool, count int8) {
+	if m.mustErr == nil {
+		data, count, m.mustErr = m.reader().ReadBool()
+	}
+	return
+}
+
+func (m *MustReader) Boolean() (data bool) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadBoolean()
+	}
+	return
+}
+
+func (m *MustReader) Int8() (data int8) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadInt8()
+	}
+	return
+}
+
+func (m *MustReader) UInt8() (data uint8) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadUInt8()
+	}
+	return
+}
+
+func (m *MustReader) Int16() (data int16) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().Int16()
+	}
+	return
+}
+
+func (m *MustReader) UInt16() (data uint16) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().UInt16()
+	}
+	return
+}
+
+func (m *MustReader) Int24() (data int32) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().Int24()
+	}
+	return
+}
+
+func (m *MustReader) UInt24() (data uint32) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().UInt24()
+	}
+	return
+}
+
+func (m *MustReader) Int32() (data int32) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().Int32()
+	}
+	return
+}
+
+func (m *MustReader) UInt32() (data uint32) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().UInt32()
+	}
+	return
+}
+
+func (m *MustReader) Int64() (data int64) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().Int64()
+	}
+	return
+}
+
+func (m *MustReader) UInt64() (data uint64) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().UInt64()
+	}
+	return
+}
+
+func (m *MustReader) Float32() (data float32) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().Float32()
+	}
+	return
+}
+
+func (m *MustReader) Float64() (data float64) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().Float64()
+	}
+	return
+}
+
+func (m *MustReader) UIntSize(size uint8) (data int64) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().UIntSize(size)
+	}
+	return
+}
+
+func (m *MustReader) IntSize(size uint8) (data int64) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().IntSize(size)
+	}
+	return
+}
+
+func (m *MustReader) Int(size uint8, sign Sign) (data int64) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().Int(size, sign)
+	}
+	return
+}
+
+func (m *MustReader) VarInt() (data int64) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadVarInt()
+	}
+	return
+}
+
+func (m *MustReader) UVarInt() (data uint64) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadUVarInt()
+	}
+	return
+}
+
+func (m *MustReader) RRSInt32() (data int32) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadRRSInt32()
+	}
+	return
+}
+
+func (m *MustReader) RRSLong() (data LogicLong) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadRRSLong()
+	}
+	return
+}
+
+func (m *MustReader) LogicLong() (data LogicLong) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().LogicLong()
+	}
+	return
+}
+
+func (m *MustReader) Long() (data int) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().Long()
+	}
+	return
+}
+
+func (m *MustReader) UnsignedLong() (data uint) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().UnsignedLong()
+	}
+	return
+}
+
+func (m *MustReader) LongLong() (data int64) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().LongLong()
+	}
+	return
+}
+
+func (m *MustReader) UnsignedLongLong() (data uint64) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().UnsignedLongLong()
+	}
+	return
+}
+
+// Str reads a string like ReadString, it isn't called String so a MustReader isn't a fmt.Stringer that reads from the stream when it's
+// printed.
+func (m *MustReader) Str() (data string) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadString()
+	}
+	return
+}
+
+func (m *MustReader) NullableString() (data *string) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadNullableString()
+	}
+	return
+}
+
+func (m *MustReader) NullableBytes() (data []byte) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadNullableBytes()
+	}
+	return
+}
+
+func (m *MustReader) StringSize(ssize_t int) (data string) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadStringSize(ssize_t)
+	}
+	return
+}
+
+func (m *MustReader) CompressedString() (data string) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadCompressedString()
+	}
+	return
+}
+
+func (m *MustReader) NullableCompressedString() (data *string) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadNullableCompressedString()
+	}
+	return
+}
+
+func (m *MustReader) CompressedStringWith(c Compressor) (data string) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadCompressedStringWith(c)
+	}
+	return
+}
+
+func (m *MustReader) CompressedBytes() (data []byte) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadCompressedBytes()
+	}
+	return
+}
+
+func (m *MustReader) CompressedBytesWith(c Compressor) (data []byte) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadCompressedBytesWith(c)
+	}
+	return
+}
+
+func (m *MustReader) Length(p LengthPrefix) (length int) {
+	if m.mustErr == nil {
+		length, m.mustErr = m.reader().ReadLength(p)
+	}
+	return
+}
+
+func (m *MustReader) PrefixedString(p LengthPrefix) (data string) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadPrefixedString(p)
+	}
+	return
+}
+
+func (m *MustReader) PrefixedBytes(p LengthPrefix) (data []byte) {
+	if m.mustErr == nil {
+		data, m.mustErr = m.reader().ReadPrefixedBytes(p)
+	}
+	return
+}
+
+func (m *MustReader) OptionalValue(v Decodable) (present bool) {
+	if m.mustErr == nil {
+		present, m.mustErr = m.reader().ReadOptionalValue(v)
+	}
+	return
+}
+
+func (m *MustReader) Value(v Decodable) {
+	if m.mustErr == nil {
+		m.mustErr = m.reader().ReadValue(v)
+	}
+}
+
+func (m *MustReader) Binary(v encoding.BinaryUnmarshaler) {
+	if m.mustErr == nil {
+		m.mustErr = m.reader().ReadBinary(v)
+	}
+}
+
+func (m *MustReader) Unmarshal(v any) {
+	if m.mustErr == nil {
+		m.mustErr = m.reader().Unmarshal(v)
+	}
+}
+
+func (m *MustReader) Skip(n int) {
+	if m.mustErr == nil {
+		m.mustErr = m.reader().Skip(n)
+	}
+}
+
+func (m *MustReader) ExpectEOF() {
+	if m.mustErr == nil {
+		m.mustErr = m.reader().ExpectEOF()
+	}
+}
+
+// Sub is like Reader.Sub, a failure gives back an empty Reader so decoding the section fails too instead of panicking.
+func (m *MustReader) Sub(n int) (sub *Reader) {
+	if m.mustErr == nil {
+		sub, m.mustErr = m.reader().Sub(n)
+	}
+	if sub == nil {
+		sub = NewReader(nil)
+	}
+	return
+}
+
+// A MustWriter is a Writer with a sticky error, see MustReader. The methods are the Writer's Write* methods without the Write prefix,
+// or its short methods for those that take an Endianness.
+type MustWriter Writer
+
+// Must returns a MustWriter writing to the same stream as w, it keeps its error in w.
+func (w *Writer) Must() *MustWriter {
+	return (*MustWriter)(w)
+}
+
+// Err returns the first error a MustWriter call ran into, or nil.
+func (m *MustWriter) Err() error {
+	return m.mustErr
+}
+
+func (m *MustWriter) Offset() int64 {
+	return m.writer().Offset()
+}
+
+// SetOrder sets the byte order the short methods write with from now on, it's the Writer's own so the Writer writes with it too.
+func (m *MustWriter) SetOrder(order binary.ByteOrder) {
+	m.writer().SetOrder(order)
+}
+
+// Order returns the byte order the short methods write with.
+func (m *MustWriter) Order() Endianness {
+	return m.writer().Order()
+}
+
+func (m *MustWriter) writer() *Writer {
+	return (*Writer)(m)
+}
+
+func (m *MustWriter) Bytes(bytes []byte) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteBytes(bytes)
+	}
+}
+
+func (m *MustWriter) Bool(data bool, count int8) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteBool(data, count)
+	}
+}
+
+func (m *MustWriter) Boolean(data bool) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteBoolean(data)
+	}
+}
+
+func (m *MustWriter) Int8(data int8) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteInt8(data)
+	}
+}
+
+func (m *MustWriter) UInt8(data uint8) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteUInt8(data)
+	}
+}
+
+func (m *MustWriter) Int16(data int16) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().Int16(data)
+	}
+}
+
+func (m *MustWriter) UInt16(data uint16) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().UInt16(data)
+	}
+}
+
+func (m *MustWriter) Int24(data int32) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().Int24(data)
+	}
+}
+
+func (m *MustWriter) UInt24(data uint32) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().UInt24(data)
+	}
+}
+
+func (m *MustWriter) Int32(data int32) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().Int32(data)
+	}
+}
+
+func (m *MustWriter) UInt32(data uint32) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().UInt32(data)
+	}
+}
+
+func (m *MustWriter) Int64(data int64) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().Int64(data)
+	}
+}
+
+func (m *MustWriter) UInt64(data uint64) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().UInt64(data)
+	}
+}
+
+func (m *MustWriter) Float32(data float32) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().Float32(data)
+	}
+}
+
+func (m *MustWriter) Float64(data float64) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().Float64(data)
+	}
+}
+
+func (m *MustWriter) LogicLong(data LogicLong) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().LogicLong(data)
+	}
+}
+
+func (m *MustWriter) Long(data int64) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().Long(data)
+	}
+}
+
+func (m *MustWriter) UnsignedLong(data uint64) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().UnsignedLong(data)
+	}
+}
+
+func (m *MustWriter) LongLong(data int64) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().LongLong(data)
+	}
+}
+
+func (m *MustWriter) UnsignedLongLong(data uint64) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().UnsignedLongLong(data)
+	}
+}
+
+func (m *MustWriter) UIntSize(data int64, size uint8) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().UIntSize(data, size)
+	}
+}
+
+func (m *MustWriter) IntSize(data int64, size uint8) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().IntSize(data, size)
+	}
+}
+
+func (m *MustWriter) Int(value int64, size uint8, sign Sign) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().Int(value, size, sign)
+	}
+}
+
+func (m *MustWriter) VarInt(data int64) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteVarInt(data)
+	}
+}
+
+func (m *MustWriter) UVarInt(data uint64) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteUVarInt(data)
+	}
+}
+
+func (m *MustWriter) RRSInt32(data int32) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteRRSInt32(data)
+	}
+}
+
+func (m *MustWriter) RRSLong(data LogicLong) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteRRSLong(data)
+	}
+}
+
+// Str writes a string like WriteString, it's named after MustReader.Str.
+func (m *MustWriter) Str(data string) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteString(data)
+	}
+}
+
+func (m *MustWriter) NullableString(data *string) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteNullableString(data)
+	}
+}
+
+func (m *MustWriter) NullableBytes(data []byte) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteNullableBytes(data)
+	}
+}
+
+func (m *MustWriter) StringSize(data string, bytesize int8) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteStringSize(data, bytesize)
+	}
+}
+
+func (m *MustWriter) CompressedString(data string) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteCompressedString(data)
+	}
+}
+
+func (m *MustWriter) NullableCompressedString(data *string) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteNullableCompressedString(data)
+	}
+}
+
+func (m *MustWriter) CompressedStringWith(data string, c Compressor) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteCompressedStringWith(data, c)
+	}
+}
+
+func (m *MustWriter) CompressedBytes(data []byte) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteCompressedBytes(data)
+	}
+}
+
+func (m *MustWriter) CompressedBytesWith(data []byte, c Compressor) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteCompressedBytesWith(data, c)
+	}
+}
+
+func (m *MustWriter) Length(length int, p LengthPrefix) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteLength(length, p)
+	}
+}
+
+func (m *MustWriter) PrefixedString(data string, p LengthPrefix) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WritePrefixedString(data, p)
+	}
+}
+
+func (m *MustWriter) PrefixedBytes(data []byte, p LengthPrefix) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WritePrefixedBytes(data, p)
+	}
+}
+
+func (m *MustWriter) Value(v Encodable) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteValue(v)
+	}
+}
+
+func (m *MustWriter) OptionalValue(v Encodable) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteOptionalValue(v)
+	}
+}
+
+func (m *MustWriter) Binary(v encoding.BinaryMarshaler) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().WriteBinary(v)
+	}
+}
+
+func (m *MustWriter) Marshal(v any) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().Marshal(v)
+	}
+}
+
+func (m *MustWriter) BeginSection(prefix LengthPrefix) {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().BeginSection(prefix)
+	}
+}
+
+func (m *MustWriter) EndSection() {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().EndSection()
+	}
+}
+
+func (m *MustWriter) Flush() {
+	if m.mustErr == nil {
+		m.mustErr = m.writer().Flush()
+	}
+}
@@ -0,0 +1,110 @@
+package bytestream
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestMustReader(t *testing.T) {
+	w := NewWriter().Must()
+	w.Int32(7)
+	w.Str("hi")
+	w.Boolean(true)
+	w.RRSInt32(-65)
+	if err := w.Err(); err != nil {
+		t.Fatalf("MustWriter.Err() = %v", err)
+	}
+
+	r := NewReader((*Writer)(w).Buffer.Bytes()).Must()
+	id, name, flag, vint := r.Int32(), r.Str(), r.Boolean(), r.RRSInt32()
+	if err := r.Err(); err != nil {
+		t.Fatalf("MustReader.Err() = %v", err)
+	}
+	if id != 7 || name != "hi" || !flag || vint != -65 {
+		t.Errorf("MustReader read %v %q %v %v, want 7 \"hi\" true -65", id, name, flag, vint)
+	}
+}
+
+func TestMustReader_Sticky(t *testing.T) {
+	r := NewReader([]byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x03}).Must()
+	if got := r.Int16(); got != 1 {
+		t.Errorf("MustReader.Int16() = %v, want 1", got)
+	}
+	r.Str()
+	// The string failed, everything after it is skipped even though there'd be enough data for it
+	if got := r.Int8(); got != 0 {
+		t.Errorf("MustReader.Int8() after a failure = %v, want 0", got)
+	}
+	if got := r.Sub(1); got == nil || got.Remaining() != 0 {
+		t.Errorf("MustReader.Sub() after a failure = %v, want an empty Reader", got)
+	}
+	var e *Error
+	if err := r.Err(); !errors.Is(err, ErrShortRead) || !errors.As(err, &e) || e.Op != "ReadString" || e.Offset != 6 {
+		t.Errorf("MustReader.Err() = %v, want ReadString %v at offset 6", err, ErrShortRead)
+	}
+	if r.Offset() != 6 {
+		t.Errorf("MustReader.Offset() = %v, want 6", r.Offset())
+	}
+}
+
+func TestMustWriter_Sticky(t *testing.T) {
+	w := NewWriter()
+	m := w.Must()
+	m.Int8(1)
+	m.Int24(1 << 23)
+	m.Int8(2)
+	var e *Error
+	if err := m.Err(); !errors.Is(err, ErrOverflow) || !errors.As(err, &e) || e.Op != "Int24" || e.Offset != 1 {
+		t.Errorf("MustWriter.Err() = %v, want Int24 %v at offset 1", err, ErrOverflow)
+	}
+	if w.Buffer.Len() != 1 {
+		t.Errorf("Writer.Buffer = %v, want only the first byte", w.Buffer.Bytes())
+	}
+	if w.Must().Err() == nil {
+		t.Errorf("Writer.Must() lost the error")
+	}
+}
+
+func TestMust_Order(t *testing.T) {
+	w := NewWriterWithOrder(LittleEndian).Must()
+	w.Int16(0x0102)
+	w.UIntSize(0x030405, 3)
+	w.Int(-1, 2, Signed)
+	want := []byte{0x02, 0x01, 0x05, 0x04, 0x03, 0xFF, 0xFF}
+	if got := (*Writer)(w).Buffer.Bytes(); w.Err() != nil || !bytes.Equal(got, want) {
+		t.Fatalf("MustWriter wrote %v (%v), want %v", got, w.Err(), want)
+	}
+
+	r := NewReaderWithOrder(want, LittleEndian).Must()
+	i16, u, i := r.Int16(), r.UIntSize(3), r.Int(2, Signed)
+	if err := r.Err(); err != nil || i16 != 0x0102 || u != 0x030405 || i != -1 {
+		t.Errorf("MustReader read %#x %#x %v (%v), want 0x102 0x30405 -1", i16, u, i, err)
+	}
+}
+
+func TestMust_SetOrder(t *testing.T) {
+	w := NewWriter().Must()
+	w.Int16(0x0102)
+	w.SetOrder(LittleEndian)
+	w.Int16(0x0102)
+	w.SetOrder(BigEndian)
+	w.UInt24(0x030405)
+	want := []byte{0x01, 0x02, 0x02, 0x01, 0x03, 0x04, 0x05}
+	if got := (*Writer)(w).Buffer.Bytes(); w.Err() != nil || !bytes.Equal(got, want) {
+		t.Fatalf("MustWriter wrote %v (%v), want %v", got, w.Err(), want)
+	}
+
+	r := NewReader(want).Must()
+	be := r.Int16()
+	r.SetOrder(LittleEndian)
+	le := r.Int16()
+	if r.Order() != LittleEndian {
+		t.Errorf("MustReader.Order() = %v, want %v", r.Order(), LittleEndian)
+	}
+	r.SetOrder(BigEndian)
+	u := r.UInt24()
+	if err := r.Err(); err != nil || be != 0x0102 || le != 0x0102 || u != 0x030405 {
+		t.Errorf("MustReader read %#x %#x %#x (%v), want 0x102 0x102 0x30405", be, le, u, err)
+	}
+}
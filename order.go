@@ -0,0 +1,319 @@
+package bytestream
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Endianness implements binary.ByteOrder, so it can be handed to anything in encoding/binary that wants one.
+var _ binary.ByteOrder = BigEndian
+
+func (e Endianness) Uint16(b []byte) uint16 {
+	if e == LittleEndian {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+func (e Endianness) Uint32(b []byte) uint32 {
+	if e == LittleEndian {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (e Endianness) Uint64(b []byte) uint64 {
+	if e == LittleEndian {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+func (e Endianness) PutUint16(b []byte, v uint16) {
+	if e == LittleEndian {
+		binary.LittleEndian.PutUint16(b, v)
+	} else {
+		binary.BigEndian.PutUint16(b, v)
+	}
+}
+
+func (e Endianness) PutUint32(b []byte, v uint32) {
+	if e == LittleEndian {
+		binary.LittleEndian.PutUint32(b, v)
+	} else {
+		binary.BigEndian.PutUint32(b, v)
+	}
+}
+
+func (e Endianness) PutUint64(b []byte, v uint64) {
+	if e == LittleEndian {
+		binary.LittleEndian.PutUint64(b, v)
+	} else {
+		binary.BigEndian.PutUint64(b, v)
+	}
+}
+
+// uint24 and putUint24 are Uint32 and PutUint32 for 3 bytes, encoding/binary has nothing for them.
+func (e Endianness) uint24(b []byte) uint32 {
+	_ = b[2]
+	if e == LittleEndian {
+		return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+	}
+	return uint32(b[2]) | uint32(b[1])<<8 | uint32(b[0])<<16
+}
+
+func (e Endianness) putUint24(b []byte, v uint32) {
+	_ = b[2]
+	if e == LittleEndian {
+		b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16)
+	} else {
+		b[0], b[1], b[2] = byte(v>>16), byte(v>>8), byte(v)
+	}
+}
+
+func (e Endianness) String() string {
+	if e == LittleEndian {
+		return "LittleEndian"
+	}
+	return "BigEndian"
+}
+
+// EndiannessOf converts a binary.ByteOrder (binary.LittleEndian, binary.BigEndian or an Endianness) to an Endianness, any other
+// ByteOrder is asked how it lays out a uint16. A nil order is BigEndian.
+func EndiannessOf(order binary.ByteOrder) Endianness {
+	switch order := order.(type) {
+	case nil:
+		return BigEndian
+	case Endianness:
+		return order
+	}
+	if order == binary.LittleEndian {
+		return LittleEndian
+	}
+	if order == binary.BigEndian {
+		return BigEndian
+	}
+	var b [Int16Size]byte
+	order.PutUint16(b[:], 1)
+	if b[0] == 1 {
+		return LittleEndian
+	}
+	return BigEndian
+}
+
+// NewReaderWithOrder is like NewReader but sets the byte order the short methods (Int16, Int32...) read with.
+func NewReaderWithOrder(data []byte, order binary.ByteOrder) *Reader {
+	r := NewReader(data)
+	r.order = EndiannessOf(order)
+	return r
+}
+
+// NewWriterWithOrder is like NewWriter but sets the byte order the short methods (Int16, Int32...) write with.
+func NewWriterWithOrder(order binary.ByteOrder) *Writer {
+	w := NewWriter()
+	w.order = EndiannessOf(order)
+	return w
+}
+
+// SetOrder sets the byte order the short methods read with, it's BigEndian by default.
+func (r *Reader) SetOrder(order binary.ByteOrder) {
+	r.order = EndiannessOf(order)
+}
+
+// Order returns the byte order the short methods read with.
+func (r *Reader) Order() Endianness {
+	return r.order
+}
+
+// SetOrder sets the byte order the short methods write with, it's BigEndian by default.
+func (w *Writer) SetOrder(order binary.ByteOrder) {
+	w.order = EndiannessOf(order)
+}
+
+// Order returns the byte order the short methods write with.
+func (w *Writer) Order() Endianness {
+	return w.order
+}
+
+// The short methods are the Read* and Write* methods that take an Endianness, minus the Read or Write prefix and using the Reader's
+// or Writer's own order instead. MustReader and MustWriter have the same methods with the same names.
+
+func (r *Reader) Int16() (int16, error) {
+	data, err := r.readInt("Int16", Int16Size, r.order)
+	return int16(data), err
+}
+
+func (r *Reader) UInt16() (uint16, error) {
+	data, err := r.readUInt("UInt16", Int16Size, r.order)
+	return uint16(data), err
+}
+
+func (r *Reader) Int24() (int32, error) {
+	data, err := r.readInt("Int24", Int24Size, r.order)
+	return int32(data), err
+}
+
+func (r *Reader) UInt24() (uint32, error) {
+	data, err := r.readUInt("UInt24", Int24Size, r.order)
+	return uint32(data), err
+}
+
+func (r *Reader) Int32() (int32, error) {
+	data, err := r.readInt("Int32", Int32Size, r.order)
+	return int32(data), err
+}
+
+func (r *Reader) UInt32() (uint32, error) {
+	data, err := r.readUInt("UInt32", Int32Size, r.order)
+	return uint32(data), err
+}
+
+func (r *Reader) Int64() (int64, error) {
+	return r.readInt("Int64", Int64Size, r.order)
+}
+
+func (r *Reader) UInt64() (uint64, error) {
+	return r.readUInt("UInt64", Int64Size, r.order)
+}
+
+func (r *Reader) Float32() (float32, error) {
+	data, err := r.readUInt("Float32", Float32Size, r.order)
+	return math.Float32frombits(uint32(data)), err
+}
+
+func (r *Reader) Float64() (float64, error) {
+	data, err := r.readUInt("Float64", Float64Size, r.order)
+	return math.Float64frombits(data), err
+}
+
+func (r *Reader) IntSize(size uint8) (int64, error) {
+	return r.readInt("IntSize", size, r.order)
+}
+
+func (r *Reader) UIntSize(size uint8) (int64, error) {
+	data, err := r.readUInt("UIntSize", size, r.order)
+	return int64(data), err
+}
+
+func (r *Reader) Int(size uint8, sign Sign) (int64, error) {
+	return r.readIntSign("Int", size, sign, r.order)
+}
+
+func (r *Reader) Long() (int, error) {
+	size := Int32Size
+	if Is64Bit {
+		size = Int64Size
+	}
+	data, err := r.readInt("Long", uint8(size), r.order)
+	return int(data), err
+}
+
+func (r *Reader) UnsignedLong() (uint, error) {
+	size := Int32Size
+	if Is64Bit {
+		size = Int64Size
+	}
+	data, err := r.readUInt("UnsignedLong", uint8(size), r.order)
+	return uint(data), err
+}
+
+func (r *Reader) LongLong() (int64, error) {
+	return r.readInt("LongLong", Int64Size, r.order)
+}
+
+func (r *Reader) UnsignedLongLong() (uint64, error) {
+	return r.readUInt("UnsignedLongLong", Int64Size, r.order)
+}
+
+func (r *Reader) LogicLong() (LogicLong, error) {
+	high, err := r.readInt("LogicLong", Int32Size, r.order)
+	if err != nil {
+		return LogicLong{}, err
+	}
+	low, err := r.readInt("LogicLong", Int32Size, r.order)
+	if err != nil {
+		return LogicLong{}, err
+	}
+	return LogicLong{High: int32(high), Low: int32(low)}, nil
+}
+
+func (w *Writer) Int16(data int16) error {
+	return w.writeInt("Int16", int64(data), Int16Size, w.order)
+}
+
+func (w *Writer) UInt16(data uint16) error {
+	return w.writeUInt("UInt16", uint64(data), Int16Size, w.order)
+}
+
+func (w *Writer) Int24(data int32) error {
+	return w.writeInt("Int24", int64(data), Int24Size, w.order)
+}
+
+func (w *Writer) UInt24(data uint32) error {
+	return w.writeUInt("UInt24", uint64(data), Int24Size, w.order)
+}
+
+func (w *Writer) Int32(data int32) error {
+	return w.writeInt("Int32", int64(data), Int32Size, w.order)
+}
+
+func (w *Writer) UInt32(data uint32) error {
+	return w.writeUInt("UInt32", uint64(data), Int32Size, w.order)
+}
+
+func (w *Writer) Int64(data int64) error {
+	return w.writeInt("Int64", data, Int64Size, w.order)
+}
+
+func (w *Writer) UInt64(data uint64) error {
+	return w.writeUInt("UInt64", data, Int64Size, w.order)
+}
+
+func (w *Writer) Float32(data float32) error {
+	return w.writeUInt("Float32", uint64(math.Float32bits(data)), Float32Size, w.order)
+}
+
+func (w *Writer) Float64(data float64) error {
+	return w.writeUInt("Float64", math.Float64bits(data), Float64Size, w.order)
+}
+
+func (w *Writer) IntSize(data int64, size uint8) error {
+	return w.writeInt("IntSize", data, size, w.order)
+}
+
+func (w *Writer) UIntSize(data int64, size uint8) error {
+	return w.writeUInt("UIntSize", uint64(data), size, w.order)
+}
+
+func (w *Writer) Int(value int64, size uint8, sign Sign) error {
+	return w.writeIntSign("Int", value, size, sign, w.order)
+}
+
+func (w *Writer) Long(data int64) error {
+	if Is64Bit {
+		return w.writeInt("Long", data, Int64Size, w.order)
+	}
+	return w.writeInt("Long", int64(int32(data)), Int32Size, w.order)
+}
+
+func (w *Writer) UnsignedLong(data uint64) error {
+	if Is64Bit {
+		return w.writeUInt("UnsignedLong", data, Int64Size, w.order)
+	}
+	return w.writeUInt("UnsignedLong", uint64(uint32(data)), Int32Size, w.order)
+}
+
+func (w *Writer) LongLong(data int64) error {
+	return w.writeInt("LongLong", data, Int64Size, w.order)
+}
+
+func (w *Writer) UnsignedLongLong(data uint64) error {
+	return w.writeUInt("UnsignedLongLong", data, Int64Size, w.order)
+}
+
+func (w *Writer) LogicLong(data LogicLong) error {
+	if err := w.writeInt("LogicLong", int64(data.High), Int32Size, w.order); err != nil {
+		return err
+	}
+	return w.writeInt("LogicLong", int64(data.Low), Int32Size, w.order)
+}
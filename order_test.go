@@ -0,0 +1,105 @@
+package bytestream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+type swappedOrder struct{ binary.ByteOrder }
+
+func TestEndiannessOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		order binary.ByteOrder
+		want  Endianness
+	}{
+		{name: "nil", order: nil, want: BigEndian},
+		{name: "Endianness", order: LittleEndian, want: LittleEndian},
+		{name: "binary.LittleEndian", order: binary.LittleEndian, want: LittleEndian},
+		{name: "binary.BigEndian", order: binary.BigEndian, want: BigEndian},
+		{name: "other little endian order", order: swappedOrder{binary.LittleEndian}, want: LittleEndian},
+		{name: "other big endian order", order: swappedOrder{binary.BigEndian}, want: BigEndian},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EndiannessOf(tt.order); got != tt.want {
+				t.Errorf("EndiannessOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndianness_ByteOrder(t *testing.T) {
+	for _, e := range []Endianness{BigEndian, LittleEndian} {
+		var order binary.ByteOrder = binary.BigEndian
+		if e == LittleEndian {
+			order = binary.LittleEndian
+		}
+		got, want := make([]byte, 14), make([]byte, 14)
+		e.PutUint16(got, 0x0102)
+		e.PutUint32(got[2:], 0x03040506)
+		e.PutUint64(got[6:], 0x0708090A0B0C0D0E)
+		order.PutUint16(want, 0x0102)
+		order.PutUint32(want[2:], 0x03040506)
+		order.PutUint64(want[6:], 0x0708090A0B0C0D0E)
+		if !bytes.Equal(got, want) {
+			t.Errorf("%v Put = %v, want %v", e, got, want)
+		}
+		if e.Uint16(got) != 0x0102 || e.Uint32(got[2:]) != 0x03040506 || e.Uint64(got[6:]) != 0x0708090A0B0C0D0E {
+			t.Errorf("%v didn't read back what it put", e)
+		}
+		if e.String() != order.String() {
+			t.Errorf("Endianness.String() = %v, want %v", e.String(), order.String())
+		}
+	}
+}
+
+func TestWriter_Order(t *testing.T) {
+	w := NewWriterWithOrder(binary.LittleEndian)
+	w.Int16(0x0102)
+	w.UInt24(0x030405)
+	w.Int32(-2)
+	w.Float32(1)
+	w.LogicLong(LogicLong{High: 1, Low: 2})
+	w.UIntSize(0x0607, 2)
+	w.LongLong(-3)
+	w.Int(0x0809, 2, Unsigned)
+	want := []byte{
+		0x02, 0x01, 0x05, 0x04, 0x03, 0xFE, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x80, 0x3F,
+		0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x07, 0x06,
+		0xFD, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x09, 0x08,
+	}
+	if !bytes.Equal(w.Buffer.Bytes(), want) {
+		t.Fatalf("Writer.Buffer = %v, want %v", w.Buffer.Bytes(), want)
+	}
+
+	r := NewReaderWithOrder(want, LittleEndian)
+	i16, _ := r.Int16()
+	u24, _ := r.UInt24()
+	i32, _ := r.Int32()
+	f32, _ := r.Float32()
+	ll, _ := r.LogicLong()
+	u, _ := r.UIntSize(2)
+	lll, _ := r.LongLong()
+	i, err := r.Int(2, Unsigned)
+	if err != nil || i16 != 0x0102 || u24 != 0x030405 || i32 != -2 || f32 != 1 || ll != (LogicLong{High: 1, Low: 2}) || u != 0x0607 ||
+		lll != -3 || i != 0x0809 {
+		t.Errorf("Reader read %v %v %v %v %v %v %v %v (%v)", i16, u24, i32, f32, ll, u, lll, i, err)
+	}
+}
+
+func TestReader_OrderDefault(t *testing.T) {
+	r := NewReader([]byte{0x01, 0x02, 0x01, 0x02})
+	if got, _ := r.UInt16(); got != 0x0102 || r.Order() != BigEndian {
+		t.Errorf("Reader.UInt16() = %#x with %v, want 0x0102 with BigEndian", got, r.Order())
+	}
+	r.SetOrder(binary.LittleEndian)
+	if got, _ := r.UInt16(); got != 0x0201 {
+		t.Errorf("Reader.UInt16() after SetOrder = %#x, want 0x0201", got)
+	}
+	sub, _ := NewReaderWithOrder([]byte{0x01, 0x02}, LittleEndian).Sub(2)
+	if sub.Order() != LittleEndian {
+		t.Errorf("Reader.Sub() order = %v, want LittleEndian", sub.Order())
+	}
+}
@@ -0,0 +1,141 @@
+package bytestream
+
+import (
+	"bytes"
+	"io"
+)
+
+// Offset returns how many bytes have been consumed from the stream so far.
+func (r *Reader) Offset() int64 {
+	return r.offset
+}
+
+// Remaining returns how many unread bytes are buffered, for a Reader backed by an io.Reader this doesn't count data that hasn't arrived yet.
+func (r *Reader) Remaining() int {
+	return r.Reader.Len()
+}
+
+// Peek returns the next n bytes without consuming them, the slice is only valid until the next read.
+func (r *Reader) Peek(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, r.fail("Peek", ErrInvalidLength, 0, int64(n), nil)
+	}
+	if err := r.ensure("Peek", n); err != nil {
+		return nil, err
+	}
+	return r.Reader.Bytes()[:n], nil
+}
+
+// Skip consumes n bytes without looking at them.
+func (r *Reader) Skip(n int) error {
+	return r.skip("Skip", n)
+}
+
+func (r *Reader) skip(op string, n int) error {
+	if n < 0 {
+		return r.fail(op, ErrInvalidLength, 0, int64(n), nil)
+	}
+	if r.src == nil {
+		_, err := r.next(op, n)
+		return err
+	}
+	// Don't buffer a huge skip all at once when the data is streamed in
+	for n > 0 {
+		step := n
+		if step > len(r.chunk) {
+			step = len(r.chunk)
+		}
+		if _, err := r.next(op, step); err != nil {
+			return err
+		}
+		n -= step
+	}
+	return nil
+}
+
+// Seek moves to a position in the stream, making Reader an io.Seeker. Seeking forward works like Skip, seeking backwards only works for
+// in-memory readers or, for a Reader backed by an io.Reader, back to a position after the last Mark. io.SeekEnd only works in memory.
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	var target int64
+	switch whence {
+	case io.SeekStart:
+		target = offset
+	case io.SeekCurrent:
+		target = r.offset + offset
+	case io.SeekEnd:
+		if r.src != nil {
+			return r.offset, r.fail("Seek", ErrInvalidSeek, 0, 0, nil)
+		}
+		target = r.offset + int64(r.Reader.Len()) + offset
+	default:
+		return r.offset, r.fail("Seek", ErrInvalidSeek, 0, 0, nil)
+	}
+	if target < 0 {
+		return r.offset, r.fail("Seek", ErrInvalidSeek, 0, target, nil)
+	}
+
+	if target >= r.offset {
+		if r.src == nil && target-r.offset > int64(r.Reader.Len()) {
+			return r.offset, r.fail("Seek", ErrInvalidSeek, r.offset+int64(r.Reader.Len()), target, nil)
+		}
+		if err := r.skip("Seek", int(target-r.offset)); err != nil {
+			return r.offset, err
+		}
+		return r.offset, nil
+	}
+	if err := r.rewind("Seek", target); err != nil {
+		return r.offset, err
+	}
+	return r.offset, nil
+}
+
+// Mark remembers the current position so ResetToMark can come back to it. A Reader backed by an io.Reader keeps every byte read after
+// the mark around for that, until Mark is called again.
+func (r *Reader) Mark() {
+	r.marked = true
+	r.mark = r.offset
+	if r.src != nil {
+		r.origin = r.origin[:0]
+		r.originOffset = r.offset
+	} else if r.origin == nil {
+		r.keepOrigin()
+	}
+}
+
+// ResetToMark goes back to the position saved by the last call to Mark.
+func (r *Reader) ResetToMark() error {
+	if !r.marked {
+		return r.fail("ResetToMark", ErrInvalidSeek, 0, 0, nil)
+	}
+	return r.rewind("ResetToMark", r.mark)
+}
+
+// keepOrigin is for a Reader made without NewReader, whatever is unread right now is all it can go back to.
+func (r *Reader) keepOrigin() {
+	r.origin = r.Reader.Bytes()
+	r.originOffset = r.offset
+}
+
+func (r *Reader) rewind(op string, target int64) error {
+	if r.src == nil && r.origin == nil {
+		r.keepOrigin()
+	}
+	if target < r.originOffset || (r.src != nil && !r.marked) {
+		return r.fail(op, ErrInvalidSeek, r.originOffset, target, nil)
+	}
+
+	if r.src == nil {
+		r.Reader = bytes.NewBuffer(r.origin[target-r.originOffset:])
+	} else {
+		// The retained bytes after target become unread again, they'll be retained once more when they're read
+		kept := r.origin[target-r.originOffset:]
+		unread := make([]byte, 0, len(kept)+r.Reader.Len())
+		unread = append(unread, kept...)
+		unread = append(unread, r.Reader.Bytes()...)
+		r.Reader = bytes.NewBuffer(unread)
+		r.origin = r.origin[:target-r.originOffset]
+	}
+	r.offset = target
+	r.bitIndex = 0
+	return nil
+}
@@ -0,0 +1,207 @@
+package bytestream
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReader_Offset(t *testing.T) {
+	r := NewReader([]byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 'h', 'i', 0x05})
+	if r.Offset() != 0 || r.Remaining() != 9 {
+		t.Fatalf("Reader.Offset() = %v, Reader.Remaining() = %v, want 0 and 9", r.Offset(), r.Remaining())
+	}
+	if _, err := r.ReadInt16(BigEndian); err != nil {
+		t.Fatalf("Reader.ReadInt16() error = %v", err)
+	}
+	if _, err := r.ReadString(); err != nil {
+		t.Fatalf("Reader.ReadString() error = %v", err)
+	}
+	if r.Offset() != 8 || r.Remaining() != 1 {
+		t.Errorf("Reader.Offset() = %v, Reader.Remaining() = %v, want 8 and 1", r.Offset(), r.Remaining())
+	}
+}
+
+func TestReader_Peek(t *testing.T) {
+	type args struct {
+		n int
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "nil", data: []byte{}, args: args{n: 1}, want: nil, wantErr: true},
+		{name: "zero", data: []byte{0x01}, args: args{n: 0}, want: []byte{}, wantErr: false},
+		{name: "message id", data: []byte{0x27, 0x10, 0x00}, args: args{n: 2}, want: []byte{0x27, 0x10}, wantErr: false},
+		{name: "too far", data: []byte{0x27, 0x10}, args: args{n: 3}, want: nil, wantErr: true},
+		{name: "negative", data: []byte{0x27, 0x10}, args: args{n: -1}, want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(tt.data)
+			got, err := r.Peek(tt.args.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.Peek() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Reader.Peek() = %v, want %v", got, tt.want)
+			}
+			if r.Offset() != 0 || r.Remaining() != len(tt.data) {
+				t.Errorf("Reader.Peek() consumed data")
+			}
+		})
+	}
+}
+
+func TestReader_Skip(t *testing.T) {
+	type args struct {
+		n int
+	}
+	tests := []struct {
+		name    string
+		src     io.Reader
+		args    args
+		want    int8
+		wantErr bool
+	}{
+		{name: "in memory", src: nil, args: args{n: 3}, want: 3, wantErr: false},
+		{name: "in memory too far", src: nil, args: args{n: 6}, wantErr: true},
+		{name: "negative", src: nil, args: args{n: -1}, wantErr: true},
+		{name: "streamed", src: iotest.OneByteReader(bytes.NewReader([]byte{0x00, 0x01, 0x02, 0x03, 0x04})), args: args{n: 3}, want: 3, wantErr: false},
+		{name: "streamed too far", src: bytes.NewReader([]byte{0x00, 0x01, 0x02, 0x03, 0x04}), args: args{n: 6}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader([]byte{0x00, 0x01, 0x02, 0x03, 0x04})
+			if tt.src != nil {
+				r = NewReaderFromSize(tt.src, 2)
+			}
+			if err := r.Skip(tt.args.n); (err != nil) != tt.wantErr {
+				t.Errorf("Reader.Skip() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got, err := r.ReadInt8(); err != nil || got != tt.want {
+				t.Errorf("Reader.ReadInt8() after Skip() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_Seek(t *testing.T) {
+	type args struct {
+		offset int64
+		whence int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{name: "start", args: args{offset: 0, whence: io.SeekStart}, want: 0, wantErr: false},
+		{name: "start forward", args: args{offset: 4, whence: io.SeekStart}, want: 4, wantErr: false},
+		{name: "current back", args: args{offset: -1, whence: io.SeekCurrent}, want: 1, wantErr: false},
+		{name: "current forward", args: args{offset: 2, whence: io.SeekCurrent}, want: 4, wantErr: false},
+		{name: "end", args: args{offset: -1, whence: io.SeekEnd}, want: 4, wantErr: false},
+		{name: "end exactly", args: args{offset: 0, whence: io.SeekEnd}, want: 5, wantErr: false},
+		{name: "past the end", args: args{offset: 1, whence: io.SeekEnd}, want: 2, wantErr: true},
+		{name: "negative", args: args{offset: -3, whence: io.SeekCurrent}, want: 2, wantErr: true},
+		{name: "bad whence", args: args{offset: 0, whence: 3}, want: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader([]byte{0x00, 0x01, 0x02, 0x03, 0x04})
+			if err := r.Skip(2); err != nil {
+				t.Fatalf("Reader.Skip() error = %v", err)
+			}
+			got, err := r.Seek(tt.args.offset, tt.args.whence)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.Seek() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || r.Offset() != tt.want {
+				t.Errorf("Reader.Seek() = %v, offset %v, want %v", got, r.Offset(), tt.want)
+			}
+			if tt.want < 5 {
+				if b, err := r.ReadUInt8(); err != nil || int64(b) != tt.want {
+					t.Errorf("Reader.ReadUInt8() after Seek() = %v, %v, want %v", b, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestReader_Seek_Literal(t *testing.T) {
+	r := &Reader{Reader: bytes.NewBuffer([]byte{0x00, 0x01, 0x02, 0x03})}
+	if _, err := r.ReadInt8(); err != nil {
+		t.Fatalf("Reader.ReadInt8() error = %v", err)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err == nil {
+		t.Errorf("Reader.Seek() before the first position it saw should fail")
+	}
+	if _, err := r.Seek(2, io.SeekStart); err != nil {
+		t.Fatalf("Reader.Seek() error = %v", err)
+	}
+	if _, err := r.Seek(1, io.SeekStart); err != nil {
+		t.Fatalf("Reader.Seek() back error = %v", err)
+	}
+	if b, err := r.ReadUInt8(); err != nil || b != 1 {
+		t.Errorf("Reader.ReadUInt8() = %v, %v, want 1", b, err)
+	}
+}
+
+func TestReader_Mark(t *testing.T) {
+	data := []byte{0x00, 0x0A, 0x00, 0x00, 0x00, 0x02, 'h', 'i', 0x7F}
+	tests := []struct {
+		name string
+		r    *Reader
+	}{
+		{name: "in memory", r: NewReader(data)},
+		{name: "literal", r: &Reader{Reader: bytes.NewBuffer(data)}},
+		{name: "streamed", r: NewReaderFromSize(iotest.OneByteReader(bytes.NewReader(data)), 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.r
+			if err := r.ResetToMark(); err == nil {
+				t.Errorf("Reader.ResetToMark() without a mark should fail")
+			}
+			if _, err := r.ReadInt16(BigEndian); err != nil {
+				t.Fatalf("Reader.ReadInt16() error = %v", err)
+			}
+			r.Mark()
+			// Try it as an int32 first, then go back and read it as a string
+			if got, err := r.ReadInt32(BigEndian); err != nil || got != 2 {
+				t.Fatalf("Reader.ReadInt32() = %v, %v, want 2", got, err)
+			}
+			if err := r.ResetToMark(); err != nil {
+				t.Fatalf("Reader.ResetToMark() error = %v", err)
+			}
+			if r.Offset() != 2 {
+				t.Errorf("Reader.Offset() after ResetToMark() = %v, want 2", r.Offset())
+			}
+			if got, err := r.ReadString(); err != nil || got != "hi" {
+				t.Fatalf("Reader.ReadString() = %v, %v, want hi", got, err)
+			}
+			if err := r.ResetToMark(); err != nil {
+				t.Fatalf("Reader.ResetToMark() again error = %v", err)
+			}
+			if got, err := r.ReadString(); err != nil || got != "hi" {
+				t.Fatalf("Reader.ReadString() again = %v, %v, want hi", got, err)
+			}
+			if got, err := r.ReadInt8(); err != nil || got != 0x7F {
+				t.Errorf("Reader.ReadInt8() = %v, %v, want 127", got, err)
+			}
+			if _, err := r.Seek(0, io.SeekStart); tt.name == "streamed" && err == nil {
+				t.Errorf("Reader.Seek() before the mark of a streamed Reader should fail")
+			}
+		})
+	}
+}
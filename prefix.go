@@ -0,0 +1,198 @@
+package bytestream
+
+import "encoding/binary"
+
+// A LengthEncoding says how the value of a LengthPrefix is laid out.
+type LengthEncoding uint8
+
+const (
+	// LengthFixed is an integer Size bytes wide, in the prefix's Endianness and Sign.
+	LengthFixed LengthEncoding = iota
+	// LengthVarInt is a varint, zigzag encoded when the prefix is Signed (see ReadVarInt and ReadUVarInt).
+	LengthVarInt
+	// LengthRRS is the game's VInt (see ReadRRSInt32), it's always signed.
+	LengthRRS
+)
+
+// A LengthPrefix describes the length or count in front of a string, byte array or collection, so every variant a protocol uses can
+// be described once and handed to ReadPrefixedString, ReadSlice and friends.
+type LengthPrefix struct {
+	Encoding LengthEncoding
+	// Size and Endianness only matter for LengthFixed.
+	Size       uint8
+	Endianness Endianness
+	Sign       Sign
+	// When Nullable is set, Null is the value that stands for a nil string, byte array or collection (usually -1, or e.g. 0xFFFF for
+	// an unsigned 2-byte prefix).
+	Nullable bool
+	Null     int64
+}
+
+var (
+	// CountInt32 is a big endian int32 where -1 means nil, it's what ReadString and WriteString use.
+	CountInt32 = LengthPrefix{Encoding: LengthFixed, Size: Int32Size, Endianness: BigEndian, Sign: Signed, Nullable: true, Null: -1}
+	// CountInt16 is a big endian int16 where -1 means nil.
+	CountInt16 = LengthPrefix{Encoding: LengthFixed, Size: Int16Size, Endianness: BigEndian, Sign: Signed, Nullable: true, Null: -1}
+	// CountVInt is an RRS VInt where -1 means nil.
+	CountVInt = LengthPrefix{Encoding: LengthRRS, Sign: Signed, Nullable: true, Null: -1}
+	// CountUVarInt is an unsigned varint, it can't say nil so a nil value is written as empty.
+	CountUVarInt = LengthPrefix{Encoding: LengthVarInt, Sign: Unsigned}
+	// CountByte is a single unsigned byte, it can't say nil so a nil value is written as empty.
+	CountByte = LengthPrefix{Encoding: LengthFixed, Size: ByteSize, Sign: Unsigned}
+)
+
+// ReadLength reads a length encoded as p, -1 is returned for p.Null. A length that is negative (and not Null) fails with
+// ErrInvalidLength.
+func (r *Reader) ReadLength(p LengthPrefix) (int, error) {
+	return r.readLength("ReadLength", p, 0)
+}
+
+// WriteLength writes length encoded as p, -1 writes p.Null and fails with ErrInvalidLength if p isn't Nullable. A length equal to
+// p.Null fails with ErrInvalidLength too, it would read back as nil.
+func (w *Writer) WriteLength(length int, p LengthPrefix) error {
+	if length == -1 {
+		if !p.Nullable {
+			return w.fail("WriteLength", ErrInvalidLength, 0, -1, nil)
+		}
+		return w.writeLength("WriteLength", p, 0, true)
+	}
+	if length < 0 {
+		return w.fail("WriteLength", ErrInvalidLength, 0, int64(length), nil)
+	}
+	return w.writeLength("WriteLength", p, length, false)
+}
+
+// ReadPrefixedString reads a string with a length prefix encoded as p, a Null length gives back an empty string.
+func (r *Reader) ReadPrefixedString(p LengthPrefix) (string, error) {
+	return r.readPrefixedString("ReadPrefixedString", p)
+}
+
+// WritePrefixedString writes data with a length prefix encoded as p.
+func (w *Writer) WritePrefixedString(data string, p LengthPrefix) error {
+	if err := w.writeLength("WritePrefixedString", p, len(data), false); err != nil {
+		return err
+	}
+	return w.writeString("WritePrefixedString", data)
+}
+
+// ReadPrefixedBytes reads a byte array with a length prefix encoded as p, a Null length gives back nil.
+func (r *Reader) ReadPrefixedBytes(p LengthPrefix) ([]byte, error) {
+	return r.readPrefixedBytes("ReadPrefixedBytes", p)
+}
+
+func (r *Reader) readPrefixedBytes(op string, p LengthPrefix) ([]byte, error) {
+	length, err := r.readLength(op, p, 0)
+	if err != nil || length < 0 {
+		return nil, err
+	}
+	return r.readBytes(op, length)
+}
+
+// WritePrefixedBytes writes data with a length prefix encoded as p, nil is written as p.Null when p is Nullable.
+func (w *Writer) WritePrefixedBytes(data []byte, p LengthPrefix) error {
+	return w.writePrefixedBytes("WritePrefixedBytes", data, p)
+}
+
+func (w *Writer) writePrefixedBytes(op string, data []byte, p LengthPrefix) error {
+	if err := w.writeLength(op, p, len(data), data == nil); err != nil {
+		return err
+	}
+	return w.write(op, data)
+}
+
+func (r *Reader) readPrefixedString(op string, p LengthPrefix) (string, error) {
+	length, err := r.readLength(op, p, 0)
+	if err != nil {
+		return "", err
+	}
+	return r.readString(op, length)
+}
+
+// readLength reads a length encoded as p, -1 stands for p.Null. A length above max fails with ErrLimitExceeded, a max of 0 means
+// there's no limit.
+func (r *Reader) readLength(op string, p LengthPrefix, max int) (int, error) {
+	var length int64
+	switch p.Encoding {
+	case LengthFixed:
+		data, err := r.readIntSign(op, p.Size, p.Sign, p.Endianness)
+		if err != nil {
+			return 0, err
+		}
+		length = data
+	case LengthVarInt:
+		if p.Sign == Signed {
+			data, err := r.readVarInt(op)
+			if err != nil {
+				return 0, err
+			}
+			length = data
+		} else {
+			data, err := r.readUVarInt(op)
+			if err != nil {
+				return 0, err
+			}
+			if data > 1<<63-1 {
+				return 0, r.fail(op, ErrOverflow, Int64Size, Int64Size+1, nil)
+			}
+			length = int64(data)
+		}
+	case LengthRRS:
+		data, err := r.readRRSInt32(op)
+		if err != nil {
+			return 0, err
+		}
+		length = int64(data)
+	default:
+		return 0, r.fail(op, ErrInvalidLength, 0, int64(p.Encoding), nil)
+	}
+
+	if p.Nullable && length == p.Null {
+		return -1, nil
+	}
+	if length < 0 || int64(int(length)) != length {
+		return 0, r.fail(op, ErrInvalidLength, 0, length, nil)
+	}
+	if max > 0 && length > int64(max) {
+		return 0, r.fail(op, ErrLimitExceeded, int64(max), length, nil)
+	}
+	return int(length), nil
+}
+
+// writeLength writes length encoded as p, or p.Null when isNil is set and p is Nullable (a nil value is empty otherwise).
+func (w *Writer) writeLength(op string, p LengthPrefix, length int, isNil bool) error {
+	var buf [binary.MaxVarintLen64]byte
+	_bytes, err := appendLength(op, w.Offset(), buf[:0], p, length, isNil)
+	if err != nil {
+		return err
+	}
+	return w.write(op, _bytes)
+}
+
+// appendLength is the byte slice version of writeLength, base is the stream offset of dst[0].
+func appendLength(op string, base int64, dst []byte, p LengthPrefix, length int, isNil bool) ([]byte, error) {
+	data := int64(length)
+	if isNil && p.Nullable {
+		data = p.Null
+	} else if p.Nullable && data == p.Null {
+		// It would read back as nil
+		return dst, encodeFail(op, base, dst, ErrInvalidLength, 0, data, nil)
+	}
+	switch p.Encoding {
+	case LengthFixed:
+		return appendIntSign(op, base, dst, data, p.Size, p.Sign, p.Endianness)
+	case LengthVarInt:
+		if p.Sign == Signed {
+			return appendVarInt(dst, data), nil
+		}
+		if data < 0 {
+			return dst, encodeFail(op, base, dst, ErrOverflow, binary.MaxVarintLen64, Int64Size, nil)
+		}
+		return appendUVarInt(dst, uint64(data)), nil
+	case LengthRRS:
+		if data < -1<<31 || data > 1<<31-1 {
+			return dst, encodeFail(op, base, dst, ErrOverflow, Int32Size, int64(signedSize(data)), nil)
+		}
+		return appendRRSInt32(dst, int32(data)), nil
+	}
+	return dst, encodeFail(op, base, dst, ErrInvalidLength, 0, int64(p.Encoding), nil)
+}
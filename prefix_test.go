@@ -0,0 +1,152 @@
+package bytestream
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestReader_ReadLength(t *testing.T) {
+	type args struct {
+		p LengthPrefix
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		args    args
+		want    int
+		wantErr error
+	}{
+		{name: "int32", data: []byte{0x00, 0x00, 0x01, 0x00}, args: args{p: CountInt32}, want: 256, wantErr: nil},
+		{name: "int32 null", data: []byte{0xFF, 0xFF, 0xFF, 0xFF}, args: args{p: CountInt32}, want: -1, wantErr: nil},
+		{name: "int32 negative", data: []byte{0xFF, 0xFF, 0xFF, 0xFE}, args: args{p: CountInt32}, want: 0, wantErr: ErrInvalidLength},
+		{name: "int16", data: []byte{0x01, 0x00}, args: args{p: CountInt16}, want: 256, wantErr: nil},
+		{name: "int16 LE", data: []byte{0x01, 0x00}, args: args{p: LengthPrefix{Size: Int16Size, Endianness: LittleEndian, Sign: Signed}}, want: 1, wantErr: nil},
+		{name: "uint16 null", data: []byte{0xFF, 0xFF}, args: args{p: LengthPrefix{Size: Int16Size, Sign: Unsigned, Nullable: true, Null: 0xFFFF}}, want: -1, wantErr: nil},
+		{name: "uint16 max", data: []byte{0xFF, 0xFF}, args: args{p: LengthPrefix{Size: Int16Size, Sign: Unsigned}}, want: 65535, wantErr: nil},
+		{name: "byte", data: []byte{0xFF}, args: args{p: CountByte}, want: 255, wantErr: nil},
+		{name: "vint", data: []byte{0x80, 0x01}, args: args{p: CountVInt}, want: 64, wantErr: nil},
+		{name: "vint null", data: []byte{0x40}, args: args{p: CountVInt}, want: -1, wantErr: nil},
+		{name: "varint", data: []byte{0x80, 0x02}, args: args{p: LengthPrefix{Encoding: LengthVarInt, Sign: Signed, Nullable: true, Null: -1}}, want: 128, wantErr: nil},
+		{name: "varint null", data: []byte{0x01}, args: args{p: LengthPrefix{Encoding: LengthVarInt, Sign: Signed, Nullable: true, Null: -1}}, want: -1, wantErr: nil},
+		{name: "uvarint", data: []byte{0xAC, 0x02}, args: args{p: CountUVarInt}, want: 300, wantErr: nil},
+		{name: "short", data: []byte{0x00, 0x00}, args: args{p: CountInt32}, want: 0, wantErr: ErrShortRead},
+		{name: "bad size", data: []byte{0x00}, args: args{p: LengthPrefix{Size: 0}}, want: 0, wantErr: ErrInvalidLength},
+		{name: "bad encoding", data: []byte{0x00}, args: args{p: LengthPrefix{Encoding: 42}}, want: 0, wantErr: ErrInvalidLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewReader(tt.data).ReadLength(tt.args.p)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Reader.ReadLength() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Reader.ReadLength() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteLength(t *testing.T) {
+	type args struct {
+		length int
+		p      LengthPrefix
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "int32", args: args{length: 256, p: CountInt32}, want: []byte{0x00, 0x00, 0x01, 0x00}, wantErr: false},
+		{name: "int32 null", args: args{length: -1, p: CountInt32}, want: []byte{0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+		{name: "uint16 null", args: args{length: -1, p: LengthPrefix{Size: Int16Size, Sign: Unsigned, Nullable: true, Null: 0xFFFF}}, want: []byte{0xFF, 0xFF}, wantErr: false},
+		{name: "int16 LE", args: args{length: 1, p: LengthPrefix{Size: Int16Size, Endianness: LittleEndian, Sign: Signed}}, want: []byte{0x01, 0x00}, wantErr: false},
+		{name: "vint", args: args{length: 64, p: CountVInt}, want: []byte{0x80, 0x01}, wantErr: false},
+		{name: "vint null", args: args{length: -1, p: CountVInt}, want: []byte{0x40}, wantErr: false},
+		{name: "uvarint", args: args{length: 300, p: CountUVarInt}, want: []byte{0xAC, 0x02}, wantErr: false},
+		{name: "byte", args: args{length: 255, p: CountByte}, want: []byte{0xFF}, wantErr: false},
+		{name: "byte overflow", args: args{length: 256, p: CountByte}, want: []byte{}, wantErr: true},
+		{name: "byte null", args: args{length: -1, p: CountByte}, want: []byte{}, wantErr: true},
+		{name: "negative", args: args{length: -2, p: CountInt32}, want: []byte{}, wantErr: true},
+		{name: "length is null", args: args{length: 0xFFFF, p: LengthPrefix{Size: Int16Size, Sign: Unsigned, Nullable: true, Null: 0xFFFF}}, want: []byte{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter()
+			if err := w.WriteLength(tt.args.length, tt.args.p); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteLength() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(w.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteLength() wrote %v, want %v", w.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_ReadPrefixedString(t *testing.T) {
+	prefixes := []LengthPrefix{CountInt32, CountInt16, CountVInt, CountUVarInt, CountByte,
+		{Size: Int24Size, Endianness: LittleEndian, Sign: Unsigned}}
+	for _, p := range prefixes {
+		for _, s := range []string{"", "hi", string(make([]byte, 200))} {
+			w := NewWriter()
+			if err := w.WritePrefixedString(s, p); err != nil {
+				t.Fatalf("Writer.WritePrefixedString(%+v) error = %v", p, err)
+			}
+			got, err := NewReader(w.Buffer.Bytes()).ReadPrefixedString(p)
+			if err != nil || got != s {
+				t.Errorf("Reader.ReadPrefixedString(%+v) = %q, %v, want %q", p, got, err, s)
+			}
+		}
+	}
+
+	got, err := NewReader([]byte{0x40}).ReadPrefixedString(CountVInt)
+	if err != nil || got != "" {
+		t.Errorf("Reader.ReadPrefixedString() null = %q, %v, want empty", got, err)
+	}
+}
+
+func TestWriter_WritePrefixedString_Null(t *testing.T) {
+	p := LengthPrefix{Size: ByteSize, Sign: Unsigned, Nullable: true, Null: 0xFF}
+	w := NewWriter()
+	if err := w.WritePrefixedString(string(make([]byte, 255)), p); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Writer.WritePrefixedString() error = %v, want %v", err, ErrInvalidLength)
+	}
+	if w.Buffer.Len() != 0 {
+		t.Errorf("Writer.WritePrefixedString() wrote %v bytes of a length that reads back as nil", w.Buffer.Len())
+	}
+	if err := w.WritePrefixedString(string(make([]byte, 254)), p); err != nil {
+		t.Errorf("Writer.WritePrefixedString() error = %v", err)
+	}
+}
+
+func TestReader_ReadPrefixedBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		p    LengthPrefix
+		want []byte
+	}{
+		{name: "nil", data: nil, p: CountInt32, want: []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		{name: "empty", data: []byte{}, p: CountInt32, want: []byte{0x00, 0x00, 0x00, 0x00}},
+		{name: "nil vint", data: nil, p: CountVInt, want: []byte{0x40}},
+		{name: "nil byte", data: nil, p: CountByte, want: []byte{0x00}},
+		{name: "two", data: []byte{0xAB, 0xCD}, p: CountByte, want: []byte{0x02, 0xAB, 0xCD}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter()
+			if err := w.WritePrefixedBytes(tt.data, tt.p); err != nil {
+				t.Fatalf("Writer.WritePrefixedBytes() error = %v", err)
+			}
+			if !bytes.Equal(w.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WritePrefixedBytes() wrote %v, want %v", w.Buffer.Bytes(), tt.want)
+			}
+			got, err := NewReader(tt.want).ReadPrefixedBytes(tt.p)
+			if err != nil || !bytes.Equal(got, tt.data) || (got == nil) != (tt.data == nil && tt.p.Nullable) {
+				t.Errorf("Reader.ReadPrefixedBytes() = %#v, %v, want %#v", got, err, tt.data)
+			}
+		})
+	}
+}
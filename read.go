@@ -2,35 +2,156 @@ package bytestream
 
 import (
 	"bytes"
-	"compress/zlib"
 	"encoding/binary"
-	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
 )
 
+// DefaultReadSize is how much a Reader created with NewReaderFrom asks its io.Reader for at once.
+const DefaultReadSize = 4096
+
 type Reader struct {
+	// Reader holds the bytes that haven't been read yet, for a Reader backed by an io.Reader it's refilled as needed.
 	Reader *bytes.Buffer
+
+	src    io.Reader
+	srcErr error
+	chunk  []byte
+
+	// offset counts every byte consumed so far, origin holds the bytes from originOffset onwards so the Reader can go back to them.
+	offset       int64
+	origin       []byte
+	originOffset int64
+	marked       bool
+	mark         int64
+
+	// bitIndex is the next bit ReadBoolean reads out of bitByte, any other read starts over at 0. The fixed width reads don't reset it
+	// to keep their fast path cheap, ReadBoolean also starts over when the offset has moved past bitOffset, the end of bitByte.
+	bitIndex  uint8
+	bitByte   byte
+	bitOffset int64
+
+	// opts are the limits set with SetOptions, allocated is how much of opts.AllocationBudget has been used up. A sub-reader spends
+	// the budget of the Reader budget points to instead of its own.
+	opts      ReaderOptions
+	allocated int64
+	budget    *Reader
+
+	// mustErr is the first error a MustReader call ran into.
+	mustErr error
+
+	// order is what the short methods (Int16, Int32...) read with.
+	order Endianness
 }
 
 func NewReader(data []byte) *Reader {
-	return &Reader{bytes.NewBuffer(data)}
+	return &Reader{Reader: bytes.NewBuffer(data), origin: data}
 }
 
-func (r *Reader) ReadBytes(length int) ([]byte, error) {
-	_bytes := make([]byte, length)
-	n, err := r.Reader.Read(_bytes)
+// NewReaderFrom returns a Reader that pulls its data from src (a net.Conn, a file, a pipe...) as it's read instead of needing it all up front.
+func NewReaderFrom(src io.Reader) *Reader {
+	return NewReaderFromSize(src, DefaultReadSize)
+}
+
+// NewReaderFromSize is like NewReaderFrom but reads up to size bytes from src at a time.
+func NewReaderFromSize(src io.Reader, size int) *Reader {
+	if size <= 0 {
+		size = DefaultReadSize
+	}
+	return &Reader{Reader: new(bytes.Buffer), src: src, chunk: make([]byte, size)}
+}
+
+// fill keeps reading from the underlying io.Reader until at least n bytes are buffered, short reads are retried like io.ReadFull does.
+func (r *Reader) fill(n int) {
+	for r.Reader.Len() < n && r.srcErr == nil {
+		m, err := r.src.Read(r.chunk)
+		r.Reader.Write(r.chunk[:m])
+		if err != nil {
+			r.srcErr = err
+		}
+	}
+}
+
+// ensure makes sure at least n unread bytes are buffered.
+func (r *Reader) ensure(op string, n int) error {
+	if r.src != nil && r.Reader.Len() < n {
+		r.fill(n)
+	}
+	if available := r.Reader.Len(); available < n {
+		if r.srcErr != nil && r.srcErr != io.EOF {
+			return r.fail(op, ErrShortRead, int64(n), int64(available), r.srcErr)
+		}
+		return r.fail(op, ErrShortRead, int64(n), int64(available), nil)
+	}
+	return nil
+}
+
+// next consumes the next n bytes, the returned slice is only valid until the next read.
+func (r *Reader) next(op string, n int) ([]byte, error) {
+	if n < 0 {
+		return nil, r.fail(op, ErrInvalidLength, 0, int64(n), nil)
+	}
+	if err := r.ensure(op, n); err != nil {
+		return nil, err
+	}
+	data := r.Reader.Next(n)
+	r.offset += int64(n)
+	r.bitIndex = 0
+	if r.marked && r.src != nil {
+		r.origin = append(r.origin, data...)
+	}
+	return data, nil
+}
+
+// buffered is the fast path of next for the fixed width reads of an in-memory Reader, small enough to be inlined. It returns nil when
+// the read has to go through next instead: the Reader is streamed (and may have to fill up or keep what it reads for a Mark), or
+// there aren't n bytes left.
+func (r *Reader) buffered(n int) []byte {
+	if r.src != nil || r.Reader.Len() < n {
+		return nil
+	}
+	r.offset += int64(n)
+	return r.Reader.Next(n)
+}
+
+func (r *Reader) readByte(op string) (byte, error) {
+	_bytes, err := r.next(op, ByteSize)
 	if err != nil {
+		return 0, err
+	}
+	return _bytes[0], nil
+}
+
+// ReadByte makes a Reader an io.ByteReader.
+func (r *Reader) ReadByte() (byte, error) {
+	return r.readByte("ReadByte")
+}
+
+func (r *Reader) ReadBytes(length int) ([]byte, error) {
+	return r.readBytes("ReadBytes", length)
+}
+
+// readBytes and readString are where every byte array and string gets allocated, so that's where the limits are checked.
+func (r *Reader) readBytes(op string, length int) ([]byte, error) {
+	if length < 0 {
+		return nil, r.fail(op, ErrInvalidLength, 0, int64(length), nil)
+	}
+	if err := r.alloc(op, int64(length), r.opts.MaxBytesLength); err != nil {
 		return nil, err
 	}
-	if n != length {
-		return nil, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: " + fmt.Sprint(length))
+	data, err := r.next(op, length)
+	if err != nil {
+		return nil, err
 	}
+	_bytes := make([]byte, length)
+	copy(_bytes, data)
 	return _bytes, nil
 }
 
 func (r *Reader) ReadBool() (bool, int8, error) {
 	// A bool can be packed into a byte
-	_byte, err := r.Reader.ReadByte()
+	_byte, err := r.readByte("ReadBool")
 	if err != nil {
 		return false, 0, err
 	}
@@ -40,9 +161,25 @@ func (r *Reader) ReadBool() (bool, int8, error) {
 	return true, int8(_byte), nil
 }
 
+// ReadBoolean reads a bool the way the game's ByteStream does, up to 8 booleans in a row share one byte (lowest bit first).
+// Reading anything else in between starts a new byte for the next boolean.
+func (r *Reader) ReadBoolean() (bool, error) {
+	if r.bitIndex == 0 || r.offset != r.bitOffset {
+		_byte, err := r.readByte("ReadBoolean")
+		if err != nil {
+			return false, err
+		}
+		r.bitByte = _byte
+		r.bitOffset = r.offset
+	}
+	data := r.bitByte>>r.bitIndex&1 == 1
+	r.bitIndex = (r.bitIndex + 1) & 7
+	return data, nil
+}
+
 func (r *Reader) ReadInt8() (int8, error) {
 	// An int8 is effectively a byte
-	_byte, err := r.Reader.ReadByte()
+	_byte, err := r.readByte("ReadInt8")
 	if err != nil {
 		return 0, err
 	}
@@ -51,7 +188,7 @@ func (r *Reader) ReadInt8() (int8, error) {
 
 func (r *Reader) ReadUInt8() (uint8, error) {
 	// A uint8 is also effectively a byte
-	_byte, err := r.Reader.ReadByte()
+	_byte, err := r.readByte("ReadUInt8")
 	if err != nil {
 		return 0, err
 	}
@@ -60,457 +197,427 @@ func (r *Reader) ReadUInt8() (uint8, error) {
 
 func (r *Reader) ReadInt16(endianness Endianness) (int16, error) {
 	// An int16 is 2 bytes
-	_bytes := make([]byte, 2)
-	n, err := r.Reader.Read(_bytes)
-	if err != nil {
-		return 0, err
-	}
-	if n != Int16Size {
-		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 2")
-	}
-
-	switch endianness {
-	case BigEndian:
-		return int16(binary.BigEndian.Uint16(_bytes)), nil
-	case LittleEndian:
-		return int16(binary.LittleEndian.Uint16(_bytes)), nil
-	default:
-		return 0, fmt.Errorf("invalid endianness")
+	if _bytes := r.buffered(Int16Size); _bytes != nil {
+		return int16(endianness.Uint16(_bytes)), nil
 	}
+	data, err := r.readInt("ReadInt16", Int16Size, endianness)
+	return int16(data), err
 }
 
 func (r *Reader) ReadUInt16(endianness Endianness) (uint16, error) {
 	// A uint16 is also 2 bytes
-	_bytes := make([]byte, 2)
-	n, err := r.Reader.Read(_bytes)
-	if err != nil {
-		return 0, err
-	}
-	if n != Int16Size {
-		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 2")
-	}
-
-	switch endianness {
-	case BigEndian:
-		return binary.BigEndian.Uint16(_bytes), nil
-	case LittleEndian:
-		return binary.LittleEndian.Uint16(_bytes), nil
-	default:
-		return 0, fmt.Errorf("invalid endianness")
+	if _bytes := r.buffered(Int16Size); _bytes != nil {
+		return endianness.Uint16(_bytes), nil
 	}
+	data, err := r.readUInt("ReadUInt16", Int16Size, endianness)
+	return uint16(data), err
 }
 
 // We are using an int32 to represent an int24 since the stdlib doesn't provide a type for this. However, this int32 will only read 3 bytes and cannot go above the max size for an int24 (8388607 or 0x7FFFFF) :)
 func (r *Reader) ReadInt24(endianness Endianness) (int32, error) {
 	// An int24 is 3 bytes
-	_bytes := make([]byte, 3)
-	n, err := r.Reader.Read(_bytes)
-	if err != nil {
-		return 0, err
-	}
-	if n != Int24Size {
-		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 3")
-	}
-
-	switch endianness {
-	case BigEndian:
-		uint24 := int32(uint32(_bytes[2]) | uint32(_bytes[1])<<8 | uint32(_bytes[0])<<16)
-		if uint24&0x800000 == 0 {
-			return uint24, nil
-		}
-		return uint24 - 0x1000000, nil
-	case LittleEndian:
-		uint24 := int32(uint32(_bytes[0]) | uint32(_bytes[1])<<8 | uint32(_bytes[2])<<16)
-		if uint24&0x800000 == 0 {
-			return uint24, nil
-		}
-		return uint24 - 0x1000000, nil
-	default:
-		return 0, fmt.Errorf("invalid endianness")
+	if _bytes := r.buffered(Int24Size); _bytes != nil {
+		return int32(endianness.uint24(_bytes)<<8) >> 8, nil
 	}
+	data, err := r.readInt("ReadInt24", Int24Size, endianness)
+	return int32(data), err
 }
 
 // We are using a uint32 to represent a uint24 since the stdlib doesn't provide a type for this. However, this uint32 will only read 3 bytes and cannot go above the max size for a uint24 (16777215 or 0xFFFFFF) :)
 func (r *Reader) ReadUInt24(endianness Endianness) (uint32, error) {
 	// A uint24 is 3 bytes
-	_bytes := make([]byte, 3)
-	n, err := r.Reader.Read(_bytes)
-	if err != nil {
-		return 0, err
-	}
-	if n != Int24Size {
-		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 3")
-	}
-
-	switch endianness {
-	case BigEndian:
-		return uint32(_bytes[2]) | uint32(_bytes[1])<<8 | uint32(_bytes[0])<<16, nil
-	case LittleEndian:
-		return uint32(_bytes[0]) | uint32(_bytes[1])<<8 | uint32(_bytes[2])<<16, nil
-	default:
-		return 0, fmt.Errorf("invalid endianness")
+	if _bytes := r.buffered(Int24Size); _bytes != nil {
+		return endianness.uint24(_bytes), nil
 	}
+	data, err := r.readUInt("ReadUInt24", Int24Size, endianness)
+	return uint32(data), err
 }
 
 func (r *Reader) ReadInt32(endianness Endianness) (int32, error) {
 	// An int32 is 4 bytes
-	_bytes := make([]byte, 4)
-	n, err := r.Reader.Read(_bytes)
-	if err != nil {
-		return 0, err
-	}
-	if n != Int32Size {
-		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 4")
-	}
-
-	switch endianness {
-	case BigEndian:
-		return int32(binary.BigEndian.Uint32(_bytes)), nil
-	case LittleEndian:
-		return int32(binary.LittleEndian.Uint32(_bytes)), nil
-	default:
-		return 0, fmt.Errorf("invalid endianness")
+	if _bytes := r.buffered(Int32Size); _bytes != nil {
+		return int32(endianness.Uint32(_bytes)), nil
 	}
+	data, err := r.readInt("ReadInt32", Int32Size, endianness)
+	return int32(data), err
 }
 
 func (r *Reader) ReadUInt32(endianness Endianness) (uint32, error) {
 	// A uint32 is 4 bytes
-	_bytes := make([]byte, 4)
-	n, err := r.Reader.Read(_bytes)
-	if err != nil {
-		return 0, err
-	}
-	if n != Int32Size {
-		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 4")
-	}
-
-	switch endianness {
-	case BigEndian:
-		return binary.BigEndian.Uint32(_bytes), nil
-	case LittleEndian:
-		return binary.LittleEndian.Uint32(_bytes), nil
-	default:
-		return 0, fmt.Errorf("invalid endianness")
+	if _bytes := r.buffered(Int32Size); _bytes != nil {
+		return endianness.Uint32(_bytes), nil
 	}
+	data, err := r.readUInt("ReadUInt32", Int32Size, endianness)
+	return uint32(data), err
 }
 
 func (r *Reader) ReadInt64(endianness Endianness) (int64, error) {
 	// An int64 is 8 bytes
-	_bytes := make([]byte, 8)
-	n, err := r.Reader.Read(_bytes)
-	if err != nil {
-		return 0, err
-	}
-	if n != Int64Size {
-		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 8")
-	}
-
-	switch endianness {
-	case BigEndian:
-		return int64(binary.BigEndian.Uint64(_bytes)), nil
-	case LittleEndian:
-		return int64(binary.LittleEndian.Uint64(_bytes)), nil
-	default:
-		return 0, fmt.Errorf("invalid endianness")
+	if _bytes := r.buffered(Int64Size); _bytes != nil {
+		return int64(endianness.Uint64(_bytes)), nil
 	}
+	return r.readInt("ReadInt64", Int64Size, endianness)
 }
 
 func (r *Reader) ReadUInt64(endianness Endianness) (uint64, error) {
 	// A uint64 is 8 bytes
-	_bytes := make([]byte, 8)
-	n, err := r.Reader.Read(_bytes)
-	if err != nil {
-		return 0, err
+	if _bytes := r.buffered(Int64Size); _bytes != nil {
+		return endianness.Uint64(_bytes), nil
 	}
-	if n != Int64Size {
-		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 8")
+	return r.readUInt("ReadUInt64", Int64Size, endianness)
+}
+
+// ReadFloat32 reads an IEEE-754 single precision float, the bits are kept as they are so NaN payloads survive a round trip.
+func (r *Reader) ReadFloat32(endianness Endianness) (float32, error) {
+	if _bytes := r.buffered(Float32Size); _bytes != nil {
+		return math.Float32frombits(endianness.Uint32(_bytes)), nil
 	}
+	data, err := r.readUInt("ReadFloat32", Float32Size, endianness)
+	return math.Float32frombits(uint32(data)), err
+}
 
-	switch endianness {
-	case BigEndian:
-		return binary.BigEndian.Uint64(_bytes), nil
-	case LittleEndian:
-		return binary.LittleEndian.Uint64(_bytes), nil
-	default:
-		return 0, fmt.Errorf("invalid endianness")
+// ReadFloat64 reads an IEEE-754 double precision float, see ReadFloat32.
+func (r *Reader) ReadFloat64(endianness Endianness) (float64, error) {
+	if _bytes := r.buffered(Float64Size); _bytes != nil {
+		return math.Float64frombits(endianness.Uint64(_bytes)), nil
 	}
+	data, err := r.readUInt("ReadFloat64", Float64Size, endianness)
+	return math.Float64frombits(data), err
 }
 
 func (r *Reader) ReadVarInt() (int64, error) {
-	// A varint is a variable length integer.
-	n, err := binary.ReadVarint(r.Reader)
+	return r.readVarInt("ReadVarInt")
+}
+
+func (r *Reader) readVarInt(op string) (int64, error) {
+	// A varint is a variable length integer, zigzag encoded so small negative numbers stay small.
+	ux, err := r.readUVarInt(op)
 	if err != nil {
 		return 0, err
 	}
-	return n, nil
+	x := int64(ux >> 1)
+	if ux&1 != 0 {
+		x = ^x
+	}
+	return x, nil
 }
 
 func (r *Reader) ReadUVarInt() (uint64, error) {
 	// An unsigned varint is a variable length integer.
-	n, err := binary.ReadUvarint(r.Reader)
-	if err != nil {
-		return 0, err
-	}
-	return n, nil
+	return r.readUVarInt("ReadUVarInt")
 }
 
-func (r *Reader) ReadLong(endianness Endianness) (int, error) {
-	// A long is 4 bytes on a 32-bit machine and 8 bytes on a 64-bit machine.
-	if Is64Bit {
-		_bytes := make([]byte, 8)
-		n, err := r.Reader.Read(_bytes)
+func (r *Reader) readUVarInt(op string) (uint64, error) {
+	var data uint64
+	for i, shift := 0, uint(0); i < binary.MaxVarintLen64; i, shift = i+1, shift+7 {
+		_byte, err := r.readByte(op)
 		if err != nil {
 			return 0, err
 		}
-		if n != Int64Size {
-			return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 8")
+		if _byte < 0x80 {
+			if i == binary.MaxVarintLen64-1 && _byte > 1 {
+				break
+			}
+			return data | uint64(_byte)<<shift, nil
 		}
+		data |= uint64(_byte&0x7F) << shift
+	}
+	return 0, r.fail(op, ErrOverflow, binary.MaxVarintLen64, binary.MaxVarintLen64+1, nil)
+}
+
+// ReadRRSInt32 reads the game's VInt, unlike a protobuf varint the sign is kept in bit 6 of the first byte which only carries 6 bits of
+// the value, every byte after it carries 7. It's never longer than 5 bytes.
+func (r *Reader) ReadRRSInt32() (int32, error) {
+	return r.readRRSInt32("ReadRRSInt32")
+}
 
-		switch endianness {
-		case BigEndian:
-			return int(binary.BigEndian.Uint64(_bytes)), nil
-		case LittleEndian:
-			return int(binary.LittleEndian.Uint64(_bytes)), nil
-		default:
-			return 0, fmt.Errorf("invalid endianness")
+func (r *Reader) readRRSInt32(op string) (int32, error) {
+	_byte, err := r.readByte(op)
+	if err != nil {
+		return 0, err
+	}
+	negative := _byte&0x40 != 0
+	data := uint32(_byte & 0x3F)
+	for shift, i := 6, 1; _byte&0x80 != 0; shift, i = shift+7, i+1 {
+		if i == 5 {
+			return 0, r.fail(op, ErrOverflow, 5, 6, nil)
 		}
-	} else {
-		_bytes := make([]byte, 4)
-		n, err := r.Reader.Read(_bytes)
+		_byte, err = r.readByte(op)
 		if err != nil {
 			return 0, err
 		}
-		if n != Int32Size {
-			return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 4")
-		}
+		data |= uint32(_byte&0x7F) << shift
+	}
+	if negative {
+		data = ^data
+	}
+	return int32(data), nil
+}
 
-		switch endianness {
-		case BigEndian:
-			return int(binary.BigEndian.Uint32(_bytes)), nil
-		case LittleEndian:
-			return int(binary.LittleEndian.Uint32(_bytes)), nil
-		default:
-			return 0, fmt.Errorf("invalid endianness")
-		}
+// ReadRRSLong reads a LogicLong packed as two VInts, high first.
+func (r *Reader) ReadRRSLong() (LogicLong, error) {
+	high, err := r.readRRSInt32("ReadRRSLong")
+	if err != nil {
+		return LogicLong{}, err
 	}
+	low, err := r.readRRSInt32("ReadRRSLong")
+	if err != nil {
+		return LogicLong{}, err
+	}
+	return LogicLong{High: high, Low: low}, nil
+}
+
+func (r *Reader) ReadLong(endianness Endianness) (int, error) {
+	// A long is 4 bytes on a 32-bit machine and 8 bytes on a 64-bit machine.
+	size := Int32Size
+	if Is64Bit {
+		size = Int64Size
+	}
+	data, err := r.readInt("ReadLong", uint8(size), endianness)
+	return int(data), err
 }
 
 func (r *Reader) ReadUnsignedLong(endianness Endianness) (uint, error) {
 	// An unsigned long is 4 bytes on a 32-bit machine and 8 bytes on a 64-bit machine.
+	size := Int32Size
 	if Is64Bit {
-		_bytes := make([]byte, 8)
-		n, err := r.Reader.Read(_bytes)
-		if err != nil {
-			return 0, err
-		}
-		if n != Int64Size {
-			return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 8")
-		}
-
-		switch endianness {
-		case BigEndian:
-			return uint(binary.BigEndian.Uint64(_bytes)), nil
-		case LittleEndian:
-			return uint(binary.LittleEndian.Uint64(_bytes)), nil
-		default:
-			return 0, fmt.Errorf("invalid endianness")
-		}
-	} else {
-		_bytes := make([]byte, 4)
-		n, err := r.Reader.Read(_bytes)
-		if err != nil {
-			return 0, err
-		}
-		if n != Int32Size {
-			return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 4")
-		}
-
-		switch endianness {
-		case BigEndian:
-			return uint(binary.BigEndian.Uint32(_bytes)), nil
-		case LittleEndian:
-			return uint(binary.LittleEndian.Uint32(_bytes)), nil
-		default:
-			return 0, fmt.Errorf("invalid endianness")
-		}
+		size = Int64Size
 	}
+	data, err := r.readUInt("ReadUnsignedLong", uint8(size), endianness)
+	return uint(data), err
 }
 
 func (r *Reader) ReadLongLong(endianness Endianness) (int64, error) {
 	// A long long is guaranteed to be 8 bytes
-	_bytes := make([]byte, 8)
-	n, err := r.Reader.Read(_bytes)
-	if err != nil {
-		return 0, err
-	}
-	if n != Int64Size {
-		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 8")
-	}
-
-	switch endianness {
-	case BigEndian:
-		return int64(binary.BigEndian.Uint64(_bytes)), nil
-	case LittleEndian:
-		return int64(binary.LittleEndian.Uint64(_bytes)), nil
-	default:
-		return 0, fmt.Errorf("invalid endianness")
-	}
+	return r.readInt("ReadLongLong", Int64Size, endianness)
 }
 
 func (r *Reader) ReadUnsignedLongLong(endianness Endianness) (uint64, error) {
 	// An unsigned long long is guaranteed to be 8 bytes
-	_bytes := make([]byte, 8)
-	n, err := r.Reader.Read(_bytes)
-	if err != nil {
-		return 0, err
-	}
-	if n != Int64Size {
-		return 0, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 8")
-	}
-
-	switch endianness {
-	case BigEndian:
-		return binary.BigEndian.Uint64(_bytes), nil
-	case LittleEndian:
-		return binary.LittleEndian.Uint64(_bytes), nil
-	default:
-		return 0, fmt.Errorf("invalid endianness")
-	}
+	return r.readUInt("ReadUnsignedLongLong", Int64Size, endianness)
 }
 
 func (r *Reader) ReadString() (string, error) {
-	ssize_t, err := r.ReadInt32(BigEndian)
-	if err != nil {
-		return "", err
-	}
-	if ssize_t == -1 {
-		return "", nil
-	}
-	if ssize_t < 0 {
-		return "", fmt.Errorf("invalid string size: %d", ssize_t)
-	}
+	return r.readPrefixedString("ReadString", CountInt32)
+}
 
-	_bytes := make([]byte, ssize_t)
-	n, err := r.Reader.Read(_bytes)
-	if err != nil {
-		return "", err
+// ReadNullableString is like ReadString but gives back nil instead of "" for a null string (a length of -1).
+func (r *Reader) ReadNullableString() (*string, error) {
+	const op = "ReadNullableString"
+	length, err := r.readLength(op, CountInt32, 0)
+	if err != nil || length < 0 {
+		return nil, err
 	}
-	if n != int(ssize_t) {
-		return "", fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: " + fmt.Sprint(ssize_t))
+	s, err := r.readString(op, length)
+	if err != nil {
+		return nil, err
 	}
-	return string(_bytes), nil
+	return &s, nil
+}
+
+// ReadNullableBytes reads a byte array with an int32 length prefix, a length of -1 gives back nil.
+func (r *Reader) ReadNullableBytes() ([]byte, error) {
+	return r.readPrefixedBytes("ReadNullableBytes", CountInt32)
 }
 
 func (r *Reader) ReadStringSize(ssize_t int) (string, error) {
+	return r.readString("ReadStringSize", ssize_t)
+}
+
+func (r *Reader) readString(op string, ssize_t int) (string, error) {
 	if ssize_t == -1 {
 		return "", nil
 	}
 	if ssize_t < 0 {
-		return "", fmt.Errorf("invalid string size: %d", ssize_t)
+		return "", r.fail(op, ErrInvalidLength, 0, int64(ssize_t), nil)
+	}
+	if err := r.alloc(op, int64(ssize_t), r.opts.MaxStringLength); err != nil {
+		return "", err
 	}
 
-	_bytes := make([]byte, ssize_t)
-	n, err := r.Reader.Read(_bytes)
+	_bytes, err := r.next(op, ssize_t)
 	if err != nil {
 		return "", err
 	}
-	if n != int(ssize_t) {
-		return "", fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: " + fmt.Sprint(ssize_t))
-	}
 	return string(_bytes), nil
 }
 
 func (r *Reader) ReadCompressedString() (string, error) {
-	compressedLen, err := r.ReadInt32(BigEndian)
+	data, err := r.readCompressed("ReadCompressedString", Zlib)
+	return string(data), err
+}
+
+// ReadNullableCompressedString is like ReadCompressedString but gives back nil instead of "" for a null string.
+func (r *Reader) ReadNullableCompressedString() (*string, error) {
+	data, err := r.readCompressed("ReadNullableCompressedString", Zlib)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	s := string(data)
+	return &s, nil
+}
+
+// ReadCompressedStringWith is like ReadCompressedString but decompresses with c instead of zlib.
+func (r *Reader) ReadCompressedStringWith(c Compressor) (string, error) {
+	data, err := r.readCompressed("ReadCompressedStringWith", c)
+	return string(data), err
+}
+
+// ReadCompressedBytes reads a byte array stored like ReadCompressedString stores strings, a null array gives back nil.
+func (r *Reader) ReadCompressedBytes() ([]byte, error) {
+	return r.readCompressed("ReadCompressedBytes", Zlib)
+}
+
+// ReadCompressedBytesWith is like ReadCompressedBytes but decompresses with c instead of zlib.
+func (r *Reader) ReadCompressedBytesWith(c Compressor) ([]byte, error) {
+	return r.readCompressed("ReadCompressedBytesWith", c)
+}
+
+// readCompressed reads a BE compressed size, a LE decompressed size and the data compressed with c, data is nil when it's null.
+func (r *Reader) readCompressed(op string, c Compressor) ([]byte, error) {
+	compressedLen, err := r.readInt(op, Int32Size, BigEndian)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if compressedLen == -1 {
-		return "", nil
+		return nil, nil
 	}
 	if compressedLen < 0 {
-		return "", fmt.Errorf("invalid string size: %d", compressedLen)
+		return nil, r.fail(op, ErrInvalidLength, 0, compressedLen, nil)
 	}
 
-	decompressedLen, err := r.ReadInt32(LittleEndian)
+	decompressedLen, err := r.readInt(op, Int32Size, LittleEndian)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if decompressedLen < 0 {
-		return "", fmt.Errorf("invalid string size: %d", decompressedLen)
+		return nil, r.fail(op, ErrInvalidLength, 0, decompressedLen, nil)
 	}
 
-	compressedBytes := make([]byte, compressedLen)
-	n, err := r.Reader.Read(compressedBytes)
-	if err != nil {
-		return "", err
+	// Both sizes come from the peer, check them before buffering or inflating anything
+	if err := r.alloc(op, compressedLen, r.opts.MaxBytesLength); err != nil {
+		return nil, err
 	}
-	if n != int(compressedLen) {
-		return "", fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: " + fmt.Sprint(compressedLen))
+	if err := r.alloc(op, decompressedLen, r.opts.MaxDecompressedSize); err != nil {
+		return nil, err
 	}
 
-	zlibReader, err := zlib.NewReader(bytes.NewReader(compressedBytes))
+	compressedBytes, err := r.next(op, int(compressedLen))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	decompressedBytes, err := ioutil.ReadAll(zlibReader)
+	decompressor, err := c.NewReader(bytes.NewReader(compressedBytes))
 	if err != nil {
-		return "", err
+		return nil, r.fail(op, ErrDecompress, 0, 0, err)
 	}
-	err = zlibReader.Close()
+
+	// Inflating past the declared size is already a mismatch, one extra byte is enough to tell
+	decompressedBytes, err := ioutil.ReadAll(io.LimitReader(decompressor, decompressedLen+1))
 	if err != nil {
-		return "", err
+		return nil, r.fail(op, ErrDecompress, 0, 0, err)
+	}
+	err = decompressor.Close()
+	if err != nil {
+		return nil, r.fail(op, ErrDecompress, 0, 0, err)
 	}
 	if len(decompressedBytes) != int(decompressedLen) {
-		return "", fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: " + fmt.Sprint(decompressedLen))
-	}
-	return string(decompressedBytes), nil
-}
-
-// The first index of the slice is the low and high ID, and the second index is the tag.
-// func (r *Reader) ReadLogicLong(endianness Endianness) ([]string, error) {
-// 	// A logic long is 8 bytes
-// 	low_bytes := make([]byte, 4)
-// 	high_bytes := make([]byte, 4)
-// 	n, err := r.Reader.Read(low_bytes)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	if n != Int32Size {
-// 		return nil, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 4")
-// 	}
-// 	n, err = r.Reader.Read(high_bytes)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	if n != Int32Size {
-// 		return nil, fmt.Errorf("invalid number of bytes read! Read: " + fmt.Sprint(n) + " Expected: 4")
-// 	}
-
-// 	var low, high int
-// 	switch endianness {
-// 	case BigEndian:
-// 		low = int(binary.BigEndian.Uint32(low_bytes))
-// 		high = int(binary.BigEndian.Uint32(high_bytes))
-// 	case LittleEndian:
-// 		low = int(binary.LittleEndian.Uint32(low_bytes))
-// 		high = int(binary.LittleEndian.Uint32(high_bytes))
-// 	default:
-// 		return nil, fmt.Errorf("invalid endianness")
-// 	}
-// 	tag, err := IDToTag(low, high)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	return []string{fmt.Sprintf("(%d, %d)", low, high), tag}, nil
-// }
-
-// TODO allow reading any int with size from int8 to int64 (mostly allow 40/48/56...)
+		return nil, r.fail(op, ErrDecompress, decompressedLen, int64(len(decompressedBytes)), nil)
+	}
+	// ReadAll gives back nil for nothing, only null data may be nil
+	if decompressedBytes == nil {
+		decompressedBytes = []byte{}
+	}
+	return decompressedBytes, nil
+}
+
+// A logic long is 8 bytes, the high int32 comes first and then the low int32.
+func (r *Reader) ReadLogicLong(endianness Endianness) (LogicLong, error) {
+	high, err := r.readInt("ReadLogicLong", Int32Size, endianness)
+	if err != nil {
+		return LogicLong{}, err
+	}
+	low, err := r.readInt("ReadLogicLong", Int32Size, endianness)
+	if err != nil {
+		return LogicLong{}, err
+	}
+	return LogicLong{High: int32(high), Low: int32(low)}, nil
+}
+
+// ReadUIntSize reads an unsigned integer that is size bytes wide, anything from 1 to 8 bytes works (so 40/48/56-bit ints are fine too).
+// An 8-byte value above math.MaxInt64 comes back with its bits as they are, uint64(data) gets it back.
 func (r *Reader) ReadUIntSize(size uint8, endianness Endianness) (int64, error) {
-	return 0, nil
+	data, err := r.readUInt("ReadUIntSize", size, endianness)
+	return int64(data), err
 }
 
+// ReadIntSize is the signed counterpart of ReadUIntSize, the top bit of the size-byte value is sign extended into the int64.
 func (r *Reader) ReadIntSize(size uint8, endianness Endianness) (int64, error) {
-	return 0, nil
+	return r.readInt("ReadIntSize", size, endianness)
+}
+
+// ReadInt reads an integer that is size bytes wide (1 to 8), signed or unsigned as sign says, for code that only learns the encoding at
+// runtime. An unsigned 8-byte value above math.MaxInt64 doesn't fit and fails with ErrOverflow, ReadUIntSize reads those.
+func (r *Reader) ReadInt(size uint8, sign Sign, endianness Endianness) (int64, error) {
+	return r.readIntSign("ReadInt", size, sign, endianness)
+}
+
+func (r *Reader) readIntSign(op string, size uint8, sign Sign, endianness Endianness) (int64, error) {
+	if sign == Signed {
+		return r.readInt(op, size, endianness)
+	}
+	data, err := r.readUInt(op, size, endianness)
+	if err != nil {
+		return 0, err
+	}
+	if data > math.MaxInt64 {
+		return 0, r.fail(op, ErrOverflow, Int64Size, Int64Size+1, nil)
+	}
+	return int64(data), nil
+}
+
+// readUInt and readInt are the shared path every fixed width integer read goes through.
+func (r *Reader) readUInt(op string, size uint8, endianness Endianness) (uint64, error) {
+	if size < 1 || size > Int64Size {
+		return 0, r.fail(op, ErrInvalidLength, Int64Size, int64(size), nil)
+	}
+	_bytes, err := r.next(op, int(size))
+	if err != nil {
+		return 0, err
+	}
+
+	// _bytes points straight into the buffer, nothing is copied or allocated
+	var data uint64
+	switch {
+	case size == Int16Size && endianness == BigEndian:
+		data = uint64(binary.BigEndian.Uint16(_bytes))
+	case size == Int16Size:
+		data = uint64(binary.LittleEndian.Uint16(_bytes))
+	case size == Int32Size && endianness == BigEndian:
+		data = uint64(binary.BigEndian.Uint32(_bytes))
+	case size == Int32Size:
+		data = uint64(binary.LittleEndian.Uint32(_bytes))
+	case size == Int64Size && endianness == BigEndian:
+		data = binary.BigEndian.Uint64(_bytes)
+	case size == Int64Size:
+		data = binary.LittleEndian.Uint64(_bytes)
+	case endianness == BigEndian:
+		for i := 0; i < int(size); i++ {
+			data = data<<8 | uint64(_bytes[i])
+		}
+	default:
+		for i := int(size) - 1; i >= 0; i-- {
+			data = data<<8 | uint64(_bytes[i])
+		}
+	}
+	return data, nil
+}
+
+func (r *Reader) readInt(op string, size uint8, endianness Endianness) (int64, error) {
+	data, err := r.readUInt(op, size, endianness)
+	if err != nil {
+		return 0, err
+	}
+	shift := 64 - 8*uint(size)
+	return int64(data<<shift) >> shift, nil
 }
@@ -2,8 +2,12 @@ package bytestream
 
 import (
 	"bytes"
+	"errors"
+	"io"
+	"math"
 	"reflect"
 	"testing"
+	"testing/iotest"
 )
 
 func TestReader_ReadBytes(t *testing.T) {
@@ -799,3 +803,586 @@ func TestReader_ReadCompressedString(t *testing.T) {
 		})
 	}
 }
+
+func TestReader_ReadUIntSize(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	type args struct {
+		size       uint8
+		endianness Endianness
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{name: "nil BE", fields: fields{Reader: bytes.NewBuffer([]byte{})}, args: args{size: 5, endianness: BigEndian}, want: 0, wantErr: true},
+		{name: "short BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x01, 0x02, 0x03, 0x04})}, args: args{size: 5, endianness: BigEndian}, want: 0, wantErr: true},
+		{name: "one byte BE", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF})}, args: args{size: 1, endianness: BigEndian}, want: 0xFF, wantErr: false},
+		{name: "40 bit BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x01, 0x02, 0x03, 0x04, 0x05})}, args: args{size: 5, endianness: BigEndian}, want: 0x0102030405, wantErr: false},
+		{name: "48 bit BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06})}, args: args{size: 6, endianness: BigEndian}, want: 0x010203040506, wantErr: false},
+		{name: "56 bit max BE", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})}, args: args{size: 7, endianness: BigEndian}, want: 0xFFFFFFFFFFFFFF, wantErr: false},
+		{name: "64 bit max BE", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})}, args: args{size: 8, endianness: BigEndian}, want: -1, wantErr: false},
+
+		{name: "nil LE", fields: fields{Reader: bytes.NewBuffer([]byte{})}, args: args{size: 5, endianness: LittleEndian}, want: 0, wantErr: true},
+		{name: "40 bit LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x05, 0x04, 0x03, 0x02, 0x01})}, args: args{size: 5, endianness: LittleEndian}, want: 0x0102030405, wantErr: false},
+		{name: "48 bit LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x06, 0x05, 0x04, 0x03, 0x02, 0x01})}, args: args{size: 6, endianness: LittleEndian}, want: 0x010203040506, wantErr: false},
+		{name: "56 bit LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01})}, args: args{size: 7, endianness: LittleEndian}, want: 0x01020304050607, wantErr: false},
+
+		{name: "size zero", fields: fields{Reader: bytes.NewBuffer([]byte{0x01})}, args: args{size: 0, endianness: BigEndian}, want: 0, wantErr: true},
+		{name: "size nine", fields: fields{Reader: bytes.NewBuffer([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09})}, args: args{size: 9, endianness: BigEndian}, want: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			got, err := r.ReadUIntSize(tt.args.size, tt.args.endianness)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadUIntSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Reader.ReadUIntSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_ReadIntSize(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	type args struct {
+		size       uint8
+		endianness Endianness
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{name: "nil BE", fields: fields{Reader: bytes.NewBuffer([]byte{})}, args: args{size: 5, endianness: BigEndian}, want: 0, wantErr: true},
+		{name: "one byte minus one BE", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF})}, args: args{size: 1, endianness: BigEndian}, want: -1, wantErr: false},
+		{name: "40 bit one BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x00, 0x01})}, args: args{size: 5, endianness: BigEndian}, want: 1, wantErr: false},
+		{name: "40 bit minus one BE", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF})}, args: args{size: 5, endianness: BigEndian}, want: -1, wantErr: false},
+		{name: "40 bit max BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x7F, 0xFF, 0xFF, 0xFF, 0xFF})}, args: args{size: 5, endianness: BigEndian}, want: 549755813887, wantErr: false},
+		{name: "40 bit max minus BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x80, 0x00, 0x00, 0x00, 0x00})}, args: args{size: 5, endianness: BigEndian}, want: -549755813888, wantErr: false},
+		{name: "48 bit minus two BE", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFE})}, args: args{size: 6, endianness: BigEndian}, want: -2, wantErr: false},
+		{name: "64 bit minus one BE", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})}, args: args{size: 8, endianness: BigEndian}, want: -1, wantErr: false},
+
+		{name: "nil LE", fields: fields{Reader: bytes.NewBuffer([]byte{})}, args: args{size: 5, endianness: LittleEndian}, want: 0, wantErr: true},
+		{name: "40 bit one LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x01, 0x00, 0x00, 0x00, 0x00})}, args: args{size: 5, endianness: LittleEndian}, want: 1, wantErr: false},
+		{name: "40 bit max minus LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x00, 0x80})}, args: args{size: 5, endianness: LittleEndian}, want: -549755813888, wantErr: false},
+		{name: "56 bit minus one LE", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})}, args: args{size: 7, endianness: LittleEndian}, want: -1, wantErr: false},
+
+		{name: "size zero", fields: fields{Reader: bytes.NewBuffer([]byte{0x01})}, args: args{size: 0, endianness: BigEndian}, want: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			got, err := r.ReadIntSize(tt.args.size, tt.args.endianness)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadIntSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Reader.ReadIntSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_ReadInt(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	type args struct {
+		size       uint8
+		sign       Sign
+		endianness Endianness
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{name: "signed byte", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF})}, args: args{size: 1, sign: Signed, endianness: BigEndian}, want: -1, wantErr: false},
+		{name: "unsigned byte", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF})}, args: args{size: 1, sign: Unsigned, endianness: BigEndian}, want: 255, wantErr: false},
+		{name: "signed int24 LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x80})}, args: args{size: 3, sign: Signed, endianness: LittleEndian}, want: -8388608, wantErr: false},
+		{name: "unsigned int24 LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x80})}, args: args{size: 3, sign: Unsigned, endianness: LittleEndian}, want: 8388608, wantErr: false},
+		{name: "unsigned int32 BE", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFE})}, args: args{size: 4, sign: Unsigned, endianness: BigEndian}, want: 4294967294, wantErr: false},
+		{name: "signed int64 BE", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})}, args: args{size: 8, sign: Signed, endianness: BigEndian}, want: -1, wantErr: false},
+		{name: "unsigned int64 max BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})}, args: args{size: 8, sign: Unsigned, endianness: BigEndian}, want: math.MaxInt64, wantErr: false},
+		{name: "unsigned int64 overflow BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})}, args: args{size: 8, sign: Unsigned, endianness: BigEndian}, want: 0, wantErr: true},
+		{name: "short", fields: fields{Reader: bytes.NewBuffer([]byte{0x01})}, args: args{size: 2, sign: Unsigned, endianness: BigEndian}, want: 0, wantErr: true},
+		{name: "size zero", fields: fields{Reader: bytes.NewBuffer([]byte{0x01})}, args: args{size: 0, sign: Signed, endianness: BigEndian}, want: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			got, err := r.ReadInt(tt.args.size, tt.args.sign, tt.args.endianness)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadInt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Reader.ReadInt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_ReadLogicLong(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	type args struct {
+		endianness Endianness
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    LogicLong
+		wantErr bool
+	}{
+		{name: "nil BE", fields: fields{Reader: bytes.NewBuffer([]byte{})}, args: args{endianness: BigEndian}, want: LogicLong{}, wantErr: true},
+		{name: "high only BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x3F})}, args: args{endianness: BigEndian}, want: LogicLong{}, wantErr: true},
+		{name: "tag BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x3F, 0x00, 0x01, 0x19, 0x43})}, args: args{endianness: BigEndian}, want: LogicLong{High: 63, Low: 72003}, wantErr: false},
+		{name: "tag LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x3F, 0x00, 0x00, 0x00, 0x43, 0x19, 0x01, 0x00})}, args: args{endianness: LittleEndian}, want: LogicLong{High: 63, Low: 72003}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			got, err := r.ReadLogicLong(tt.args.endianness)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadLogicLong() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Reader.ReadLogicLong() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewReaderFrom(t *testing.T) {
+	data := []byte{
+		0x00, 0x01, // int16
+		0x00, 0x00, 0x00, 0x05, 'h', 'e', 'l', 'l', 'o', // string
+		0xAC, 0x02, // uvarint 300
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, // int64
+	}
+	tests := []struct {
+		name string
+		src  io.Reader
+		size int
+	}{
+		{name: "whole", src: bytes.NewReader(data), size: 0},
+		{name: "one byte at a time", src: iotest.OneByteReader(bytes.NewReader(data)), size: 0},
+		{name: "half reads", src: iotest.HalfReader(bytes.NewReader(data)), size: 0},
+		{name: "data with eof", src: iotest.DataErrReader(bytes.NewReader(data)), size: 0},
+		{name: "tiny chunks", src: bytes.NewReader(data), size: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReaderFromSize(tt.src, tt.size)
+			i16, err := r.ReadInt16(BigEndian)
+			if err != nil || i16 != 1 {
+				t.Fatalf("Reader.ReadInt16() = %v, %v, want 1", i16, err)
+			}
+			s, err := r.ReadString()
+			if err != nil || s != "hello" {
+				t.Fatalf("Reader.ReadString() = %v, %v, want hello", s, err)
+			}
+			u, err := r.ReadUVarInt()
+			if err != nil || u != 300 {
+				t.Fatalf("Reader.ReadUVarInt() = %v, %v, want 300", u, err)
+			}
+			i64, err := r.ReadInt64(BigEndian)
+			if err != nil || i64 != 0x0102030405060708 {
+				t.Fatalf("Reader.ReadInt64() = %v, %v, want %v", i64, err, 0x0102030405060708)
+			}
+			if _, err := r.ReadInt8(); !errors.Is(err, io.EOF) {
+				t.Errorf("Reader.ReadInt8() at the end error = %v, want %v", err, io.EOF)
+			}
+		})
+	}
+}
+
+func TestNewReaderFrom_Errors(t *testing.T) {
+	errBroken := errors.New("broken pipe")
+	r := NewReaderFrom(io.MultiReader(bytes.NewReader([]byte{0x00, 0x01}), iotest.ErrReader(errBroken)))
+	if _, err := r.ReadInt32(BigEndian); !errors.Is(err, errBroken) {
+		t.Errorf("Reader.ReadInt32() error = %v, want %v", err, errBroken)
+	}
+
+	r = NewReaderFrom(bytes.NewReader([]byte{0x00, 0x01}))
+	if _, err := r.ReadInt32(BigEndian); err == nil {
+		t.Errorf("Reader.ReadInt32() on a truncated stream should fail")
+	}
+	if got, err := r.ReadInt16(BigEndian); err != nil || got != 1 {
+		t.Errorf("Reader.ReadInt16() after a short read = %v, %v, want 1", got, err)
+	}
+}
+
+func TestReader_ReadBoolean(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		count   int
+		want    []bool
+		wantErr bool
+	}{
+		{name: "nil", fields: fields{Reader: bytes.NewBuffer([]byte{})}, count: 1, want: nil, wantErr: true},
+		{name: "false", fields: fields{Reader: bytes.NewBuffer([]byte{0x00})}, count: 1, want: []bool{false}, wantErr: false},
+		{name: "true", fields: fields{Reader: bytes.NewBuffer([]byte{0x01})}, count: 1, want: []bool{true}, wantErr: false},
+		{name: "three packed", fields: fields{Reader: bytes.NewBuffer([]byte{0x05})}, count: 3, want: []bool{true, false, true}, wantErr: false},
+		{name: "eight packed", fields: fields{Reader: bytes.NewBuffer([]byte{0x81})}, count: 8, want: []bool{true, false, false, false, false, false, false, true}, wantErr: false},
+		{name: "nine needs a second byte", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0x00})}, count: 9, want: []bool{true, true, true, true, true, true, true, true, false}, wantErr: false},
+		{name: "nine without a second byte", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF})}, count: 9, want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			var got []bool
+			for i := 0; i < tt.count; i++ {
+				data, err := r.ReadBoolean()
+				if err != nil {
+					if !tt.wantErr {
+						t.Errorf("Reader.ReadBoolean() error = %v, wantErr %v", err, tt.wantErr)
+					}
+					return
+				}
+				got = append(got, data)
+			}
+			if tt.wantErr {
+				t.Errorf("Reader.ReadBoolean() error = nil, wantErr %v", tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reader.ReadBoolean() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_ReadBoolean_Reset(t *testing.T) {
+	// Two booleans, an int8, two more booleans which start a new byte, then the same around an int16
+	r := NewReader([]byte{0x02, 0x7F, 0x01, 0x00, 0x05, 0x01})
+	for i, want := range []bool{false, true} {
+		if got, err := r.ReadBoolean(); err != nil || got != want {
+			t.Fatalf("Reader.ReadBoolean() #%d = %v, %v, want %v", i, got, err, want)
+		}
+	}
+	if got, err := r.ReadInt8(); err != nil || got != 0x7F {
+		t.Fatalf("Reader.ReadInt8() = %v, %v, want 127", got, err)
+	}
+	for i, want := range []bool{true, false} {
+		if got, err := r.ReadBoolean(); err != nil || got != want {
+			t.Fatalf("Reader.ReadBoolean() #%d after int8 = %v, %v, want %v", i, got, err, want)
+		}
+	}
+	if got, err := r.ReadInt16(BigEndian); err != nil || got != 5 {
+		t.Fatalf("Reader.ReadInt16() = %v, %v, want 5", got, err)
+	}
+	if got, err := r.ReadBoolean(); err != nil || !got {
+		t.Fatalf("Reader.ReadBoolean() after int16 = %v, %v, want true", got, err)
+	}
+	if r.Remaining() != 0 {
+		t.Errorf("Reader.Remaining() = %v, want 0", r.Remaining())
+	}
+}
+
+func TestReader_ReadRRSInt32(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    int32
+		wantErr bool
+	}{
+		{name: "nil", fields: fields{Reader: bytes.NewBuffer([]byte{})}, want: 0, wantErr: true},
+		{name: "zero", fields: fields{Reader: bytes.NewBuffer([]byte{0x00})}, want: 0, wantErr: false},
+		{name: "one", fields: fields{Reader: bytes.NewBuffer([]byte{0x01})}, want: 1, wantErr: false},
+		{name: "minus one", fields: fields{Reader: bytes.NewBuffer([]byte{0x40})}, want: -1, wantErr: false},
+		{name: "one byte max", fields: fields{Reader: bytes.NewBuffer([]byte{0x3F})}, want: 63, wantErr: false},
+		{name: "one byte max minus", fields: fields{Reader: bytes.NewBuffer([]byte{0x7F})}, want: -64, wantErr: false},
+		{name: "two byte min", fields: fields{Reader: bytes.NewBuffer([]byte{0x80, 0x01})}, want: 64, wantErr: false},
+		{name: "two byte min minus", fields: fields{Reader: bytes.NewBuffer([]byte{0xC0, 0x01})}, want: -65, wantErr: false},
+		{name: "two byte max", fields: fields{Reader: bytes.NewBuffer([]byte{0xBF, 0x7F})}, want: 8191, wantErr: false},
+		{name: "three byte min", fields: fields{Reader: bytes.NewBuffer([]byte{0x80, 0x80, 0x01})}, want: 8192, wantErr: false},
+		{name: "three byte min minus", fields: fields{Reader: bytes.NewBuffer([]byte{0xC0, 0x80, 0x01})}, want: -8193, wantErr: false},
+		{name: "300", fields: fields{Reader: bytes.NewBuffer([]byte{0xAC, 0x04})}, want: 300, wantErr: false},
+		{name: "minus 300", fields: fields{Reader: bytes.NewBuffer([]byte{0xEB, 0x04})}, want: -300, wantErr: false},
+		{name: "million", fields: fields{Reader: bytes.NewBuffer([]byte{0x80, 0x89, 0x7A})}, want: 1000000, wantErr: false},
+		{name: "max", fields: fields{Reader: bytes.NewBuffer([]byte{0xBF, 0xFF, 0xFF, 0xFF, 0x0F})}, want: 2147483647, wantErr: false},
+		{name: "max minus", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x0F})}, want: -2147483648, wantErr: false},
+		{name: "truncated", fields: fields{Reader: bytes.NewBuffer([]byte{0x80, 0x80})}, want: 0, wantErr: true},
+		{name: "six bytes", fields: fields{Reader: bytes.NewBuffer([]byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x01})}, want: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			got, err := r.ReadRRSInt32()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadRRSInt32() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Reader.ReadRRSInt32() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_ReadRRSLong(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    LogicLong
+		wantErr bool
+	}{
+		{name: "nil", fields: fields{Reader: bytes.NewBuffer([]byte{})}, want: LogicLong{}, wantErr: true},
+		{name: "high only", fields: fields{Reader: bytes.NewBuffer([]byte{0x3F})}, want: LogicLong{}, wantErr: true},
+		{name: "tag", fields: fields{Reader: bytes.NewBuffer([]byte{0x3F, 0x83, 0xE5, 0x08})}, want: LogicLong{High: 63, Low: 72003}, wantErr: false},
+		{name: "negative", fields: fields{Reader: bytes.NewBuffer([]byte{0x40, 0x40})}, want: LogicLong{High: -1, Low: -1}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			got, err := r.ReadRRSLong()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadRRSLong() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Reader.ReadRRSLong() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_ReadFloat32(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	type args struct {
+		endianness Endianness
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    float32
+		wantErr bool
+	}{
+		{name: "nil BE", fields: fields{Reader: bytes.NewBuffer([]byte{})}, args: args{endianness: BigEndian}, want: 0, wantErr: true},
+		{name: "short BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x3F, 0xC0, 0x00})}, args: args{endianness: BigEndian}, want: 0, wantErr: true},
+		{name: "zero BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x00})}, args: args{endianness: BigEndian}, want: 0, wantErr: false},
+		{name: "minus zero BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x80, 0x00, 0x00, 0x00})}, args: args{endianness: BigEndian}, want: float32(math.Copysign(0, -1)), wantErr: false},
+		{name: "one and a half BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x3F, 0xC0, 0x00, 0x00})}, args: args{endianness: BigEndian}, want: 1.5, wantErr: false},
+		{name: "minus two BE", fields: fields{Reader: bytes.NewBuffer([]byte{0xC0, 0x00, 0x00, 0x00})}, args: args{endianness: BigEndian}, want: -2, wantErr: false},
+		{name: "infinity BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x7F, 0x80, 0x00, 0x00})}, args: args{endianness: BigEndian}, want: float32(math.Inf(1)), wantErr: false},
+		{name: "smallest subnormal BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x01})}, args: args{endianness: BigEndian}, want: math.SmallestNonzeroFloat32, wantErr: false},
+		{name: "nan payload BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x7F, 0xC0, 0x00, 0x01})}, args: args{endianness: BigEndian}, want: math.Float32frombits(0x7FC00001), wantErr: false},
+
+		{name: "nil LE", fields: fields{Reader: bytes.NewBuffer([]byte{})}, args: args{endianness: LittleEndian}, want: 0, wantErr: true},
+		{name: "one and a half LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0xC0, 0x3F})}, args: args{endianness: LittleEndian}, want: 1.5, wantErr: false},
+		{name: "minus two LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0xC0})}, args: args{endianness: LittleEndian}, want: -2, wantErr: false},
+		{name: "signalling nan LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x01, 0x00, 0x80, 0x7F})}, args: args{endianness: LittleEndian}, want: math.Float32frombits(0x7F800001), wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			got, err := r.ReadFloat32(tt.args.endianness)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadFloat32() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			// Compare the bits so NaNs and signed zeroes are checked too
+			if math.Float32bits(got) != math.Float32bits(tt.want) {
+				t.Errorf("Reader.ReadFloat32() = %#x, want %#x", math.Float32bits(got), math.Float32bits(tt.want))
+			}
+		})
+	}
+}
+
+func TestReader_ReadFloat64(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	type args struct {
+		endianness Endianness
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    float64
+		wantErr bool
+	}{
+		{name: "nil BE", fields: fields{Reader: bytes.NewBuffer([]byte{})}, args: args{endianness: BigEndian}, want: 0, wantErr: true},
+		{name: "short BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x3F, 0xF8, 0x00, 0x00})}, args: args{endianness: BigEndian}, want: 0, wantErr: true},
+		{name: "zero BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})}, args: args{endianness: BigEndian}, want: 0, wantErr: false},
+		{name: "minus zero BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})}, args: args{endianness: BigEndian}, want: math.Copysign(0, -1), wantErr: false},
+		{name: "one and a half BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x3F, 0xF8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})}, args: args{endianness: BigEndian}, want: 1.5, wantErr: false},
+		{name: "minus two BE", fields: fields{Reader: bytes.NewBuffer([]byte{0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})}, args: args{endianness: BigEndian}, want: -2, wantErr: false},
+		{name: "minus infinity BE", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xF0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})}, args: args{endianness: BigEndian}, want: math.Inf(-1), wantErr: false},
+		{name: "max BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x7F, 0xEF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})}, args: args{endianness: BigEndian}, want: math.MaxFloat64, wantErr: false},
+		{name: "nan payload BE", fields: fields{Reader: bytes.NewBuffer([]byte{0x7F, 0xF8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01})}, args: args{endianness: BigEndian}, want: math.Float64frombits(0x7FF8000000000001), wantErr: false},
+
+		{name: "nil LE", fields: fields{Reader: bytes.NewBuffer([]byte{})}, args: args{endianness: LittleEndian}, want: 0, wantErr: true},
+		{name: "one and a half LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF8, 0x3F})}, args: args{endianness: LittleEndian}, want: 1.5, wantErr: false},
+		{name: "minus two LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xC0})}, args: args{endianness: LittleEndian}, want: -2, wantErr: false},
+		{name: "signalling nan LE", fields: fields{Reader: bytes.NewBuffer([]byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF0, 0x7F})}, args: args{endianness: LittleEndian}, want: math.Float64frombits(0x7FF0000000000001), wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			got, err := r.ReadFloat64(tt.args.endianness)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadFloat64() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if math.Float64bits(got) != math.Float64bits(tt.want) {
+				t.Errorf("Reader.ReadFloat64() = %#x, want %#x", math.Float64bits(got), math.Float64bits(tt.want))
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestReader_ReadNullableString(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    *string
+		wantErr bool
+	}{
+		{name: "nil", fields: fields{Reader: bytes.NewBuffer([]byte{})}, want: nil, wantErr: true},
+		{name: "null", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFF})}, want: nil, wantErr: false},
+		{name: "empty", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x00})}, want: strPtr(""), wantErr: false},
+		{name: "one", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x01, 'a'})}, want: strPtr("a"), wantErr: false},
+		{name: "negative", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFE})}, want: nil, wantErr: true},
+		{name: "no string (EOF)", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x01})}, want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			got, err := r.ReadNullableString()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadNullableString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reader.ReadNullableString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_ReadNullableBytes(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    []byte
+		wantErr bool
+	}{
+		{name: "nil", fields: fields{Reader: bytes.NewBuffer([]byte{})}, want: nil, wantErr: true},
+		{name: "null", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFF})}, want: nil, wantErr: false},
+		{name: "empty", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x00})}, want: []byte{}, wantErr: false},
+		{name: "two", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x02, 0xAB, 0xCD})}, want: []byte{0xAB, 0xCD}, wantErr: false},
+		{name: "negative", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFE})}, want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			got, err := r.ReadNullableBytes()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadNullableBytes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reader.ReadNullableBytes() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_ReadNullableCompressedString(t *testing.T) {
+	type fields struct {
+		Reader *bytes.Buffer
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    *string
+		wantErr bool
+	}{
+		{name: "nil", fields: fields{Reader: bytes.NewBuffer([]byte{})}, want: nil, wantErr: true},
+		{name: "null", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0xFF, 0xFF, 0xFF})}, want: nil, wantErr: false},
+		{name: "empty", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x00, 0x78, 0x9c, 0x01, 0x00, 0x00, 0xff, 0xff, 0x00, 0x00, 0x00, 0x01})}, want: strPtr(""), wantErr: false},
+		{name: "one", fields: fields{Reader: bytes.NewBuffer([]byte{0x00, 0x00, 0x00, 0x0d, 0x01, 0x00, 0x00, 0x00, 0x78, 0x9c, 0x4a, 0x04, 0x04, 0x00, 0x00, 0xff, 0xff, 0x00, 0x62, 0x00, 0x62})}, want: strPtr("a"), wantErr: false},
+		{name: "negative", fields: fields{Reader: bytes.NewBuffer([]byte{0xFF, 0x00, 0x00, 0x00})}, want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{
+				Reader: tt.fields.Reader,
+			}
+			got, err := r.ReadNullableCompressedString()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadNullableCompressedString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reader.ReadNullableCompressedString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
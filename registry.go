@@ -0,0 +1,116 @@
+package bytestream
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// A Message is anything that can be sent as the payload of a Frame.
+type Message interface {
+	Encodable
+	Decodable
+}
+
+// RawMessage is what a Registry decodes a frame into when nothing is registered under its id, the payload is left as it is.
+type RawMessage struct {
+	ID      uint16
+	Version uint16
+	Payload []byte
+}
+
+func (m *RawMessage) Encode(w *Writer) error {
+	return w.WriteBytes(m.Payload)
+}
+
+// Decode takes whatever is left in r as the payload.
+func (m *RawMessage) Decode(r *Reader) error {
+	payload, err := r.ReadBytes(r.Remaining())
+	if err != nil {
+		return err
+	}
+	m.Payload = payload
+	return nil
+}
+
+// A Registry maps message ids to the types they decode into, it's safe to use from several goroutines.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[uint16]func() Message
+	ids       map[reflect.Type]uint16
+}
+
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[uint16]func() Message), ids: make(map[reflect.Type]uint16)}
+}
+
+// Register makes frames with the given id decode into whatever factory returns, factory has to return a new value every time.
+// An id or a type can only be registered once.
+func (reg *Registry) Register(id uint16, factory func() Message) error {
+	typ := reflect.TypeOf(factory())
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if _, ok := reg.factories[id]; ok {
+		return fmt.Errorf("%w: id %d is already registered", ErrDuplicateMessage, id)
+	}
+	if other, ok := reg.ids[typ]; ok {
+		return fmt.Errorf("%w: %s is already registered as %d", ErrDuplicateMessage, typ, other)
+	}
+	reg.factories[id] = factory
+	reg.ids[typ] = id
+	return nil
+}
+
+// New returns an empty message for id, ok is false if nothing is registered under it.
+func (reg *Registry) New(id uint16) (m Message, ok bool) {
+	reg.mu.RLock()
+	factory, ok := reg.factories[id]
+	reg.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// ID returns the id m's type was registered under.
+func (reg *Registry) ID(m Message) (uint16, bool) {
+	if raw, ok := m.(*RawMessage); ok {
+		return raw.ID, true
+	}
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	id, ok := reg.ids[reflect.TypeOf(m)]
+	return id, ok
+}
+
+// Decode decodes the payload of f into the type registered under its id, or into a *RawMessage if there isn't one. The frame's
+// version is returned alongside since a registered message doesn't carry it.
+func (reg *Registry) Decode(f *Frame) (m Message, version uint16, err error) {
+	m, ok := reg.New(f.ID)
+	if !ok {
+		return &RawMessage{ID: f.ID, Version: f.Version, Payload: f.Payload}, f.Version, nil
+	}
+	if err := m.Decode(f.Reader()); err != nil {
+		return nil, 0, err
+	}
+	return m, f.Version, nil
+}
+
+// Encode encodes m into a Frame carrying the id its type was registered under and version, a *RawMessage keeps its own id and
+// version.
+func (reg *Registry) Encode(m Message, version uint16) (*Frame, error) {
+	id, ok := reg.ID(m)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnknownMessage, m)
+	}
+	f := &Frame{ID: id, Version: version}
+	if raw, ok := m.(*RawMessage); ok {
+		f.Version = raw.Version
+	}
+	w := NewWriter()
+	if err := m.Encode(w); err != nil {
+		return nil, err
+	}
+	f.Payload = w.Buffer.Bytes()
+	return f, nil
+}
@@ -0,0 +1,112 @@
+package bytestream
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type loginMessage struct {
+	AccountID LogicLong
+	Token     string
+}
+
+func (m *loginMessage) Encode(w *Writer) error {
+	if err := w.WriteLogicLong(m.AccountID, BigEndian); err != nil {
+		return err
+	}
+	return w.WriteString(m.Token)
+}
+
+func (m *loginMessage) Decode(r *Reader) error {
+	var err error
+	if m.AccountID, err = r.ReadLogicLong(BigEndian); err != nil {
+		return err
+	}
+	m.Token, err = r.ReadString()
+	return err
+}
+
+type keepAliveMessage struct{}
+
+func (m *keepAliveMessage) Encode(w *Writer) error { return nil }
+func (m *keepAliveMessage) Decode(r *Reader) error { return nil }
+
+func newTestRegistry(t *testing.T) *Registry {
+	reg := NewRegistry()
+	if err := reg.Register(10101, func() Message { return new(loginMessage) }); err != nil {
+		t.Fatalf("Registry.Register() error = %v", err)
+	}
+	if err := reg.Register(10108, func() Message { return new(keepAliveMessage) }); err != nil {
+		t.Fatalf("Registry.Register() error = %v", err)
+	}
+	return reg
+}
+
+func TestRegistry_Register(t *testing.T) {
+	reg := newTestRegistry(t)
+	if err := reg.Register(10101, func() Message { return new(RawMessage) }); !errors.Is(err, ErrDuplicateMessage) {
+		t.Errorf("Registry.Register() duplicate id error = %v, want %v", err, ErrDuplicateMessage)
+	}
+	if err := reg.Register(10200, func() Message { return new(loginMessage) }); !errors.Is(err, ErrDuplicateMessage) {
+		t.Errorf("Registry.Register() duplicate type error = %v, want %v", err, ErrDuplicateMessage)
+	}
+	if _, ok := reg.New(10200); ok {
+		t.Errorf("Registry.New() found a rejected registration")
+	}
+}
+
+func TestRegistry_Decode(t *testing.T) {
+	reg := newTestRegistry(t)
+	tests := []struct {
+		name        string
+		frame       *Frame
+		want        Message
+		wantVersion uint16
+		wantErr     bool
+	}{
+		{name: "login", frame: &Frame{ID: 10101, Version: 2, Payload: []byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x02, 'h', 'i'}}, want: &loginMessage{AccountID: LogicLong{High: 1, Low: 2}, Token: "hi"}, wantVersion: 2, wantErr: false},
+		{name: "keep alive", frame: &Frame{ID: 10108, Payload: []byte{}}, want: &keepAliveMessage{}, wantErr: false},
+		{name: "unknown", frame: &Frame{ID: 20000, Version: 3, Payload: []byte{0x01, 0x02}}, want: &RawMessage{ID: 20000, Version: 3, Payload: []byte{0x01, 0x02}}, wantVersion: 3, wantErr: false},
+		{name: "truncated", frame: &Frame{ID: 10101, Payload: []byte{0x00, 0x00, 0x00, 0x01}}, want: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, version, err := reg.Decode(tt.frame)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Registry.Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) || version != tt.wantVersion {
+				t.Errorf("Registry.Decode() = %v, %v, want %v, %v", got, version, tt.want, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestRegistry_Encode(t *testing.T) {
+	reg := newTestRegistry(t)
+	tests := []struct {
+		name    string
+		message Message
+		version uint16
+		want    *Frame
+		wantErr error
+	}{
+		{name: "login", message: &loginMessage{AccountID: LogicLong{High: 1, Low: 2}, Token: "hi"}, version: 2, want: &Frame{ID: 10101, Version: 2, Payload: []byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x02, 'h', 'i'}}, wantErr: nil},
+		{name: "raw", message: &RawMessage{ID: 20000, Version: 3, Payload: []byte{0x01, 0x02}}, version: 1, want: &Frame{ID: 20000, Version: 3, Payload: []byte{0x01, 0x02}}, wantErr: nil},
+		{name: "unregistered", message: &struct{ keepAliveMessage }{}, want: nil, wantErr: ErrUnknownMessage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reg.Encode(tt.message, tt.version)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Registry.Encode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Registry.Encode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package bytestream
+
+import "encoding/binary"
+
+// A Placeholder is space reserved in a Writer's output with Reserve, to be filled in later with one of its Patch methods once the
+// value is known (usually a length or a count). The Writer doesn't flush anything until every Placeholder has been patched.
+type Placeholder struct {
+	w      *Writer
+	offset int64
+	width  uint8
+	done   bool
+}
+
+// Offset returns where in the stream the Placeholder starts.
+func (p *Placeholder) Offset() int64 {
+	return p.offset
+}
+
+// Width returns how many bytes were reserved.
+func (p *Placeholder) Width() int {
+	return int(p.width)
+}
+
+// Reserve writes width zero bytes (1 to 8) and returns a Placeholder to patch them with later.
+func (w *Writer) Reserve(width int) (*Placeholder, error) {
+	return w.reserve("Reserve", width)
+}
+
+func (w *Writer) reserve(op string, width int) (*Placeholder, error) {
+	if width < 1 || width > Int64Size {
+		return nil, w.fail(op, ErrInvalidLength, Int64Size, int64(width), nil)
+	}
+	if w.err != nil {
+		return nil, w.fail(op, nil, 0, 0, w.err)
+	}
+	p := &Placeholder{w: w, offset: w.Offset(), width: uint8(width)}
+	// It has to be pending before the zeros are written, or they could be flushed right away
+	w.reserved = append(w.reserved, p)
+	var zeros [Int64Size]byte
+	if err := w.write(op, zeros[:width]); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Placeholder) PatchInt8(data int8) error {
+	return p.patchInt("PatchInt8", int64(data), Int8Size, BigEndian)
+}
+
+func (p *Placeholder) PatchUInt8(data uint8) error {
+	return p.patchUInt("PatchUInt8", uint64(data), Int8Size, BigEndian)
+}
+
+func (p *Placeholder) PatchInt16(data int16, endianness Endianness) error {
+	return p.patchInt("PatchInt16", int64(data), Int16Size, endianness)
+}
+
+func (p *Placeholder) PatchUInt16(data uint16, endianness Endianness) error {
+	return p.patchUInt("PatchUInt16", uint64(data), Int16Size, endianness)
+}
+
+func (p *Placeholder) PatchInt24(data int32, endianness Endianness) error {
+	return p.patchInt("PatchInt24", int64(data), Int24Size, endianness)
+}
+
+func (p *Placeholder) PatchUInt24(data uint32, endianness Endianness) error {
+	return p.patchUInt("PatchUInt24", uint64(data), Int24Size, endianness)
+}
+
+func (p *Placeholder) PatchInt32(data int32, endianness Endianness) error {
+	return p.patchInt("PatchInt32", int64(data), Int32Size, endianness)
+}
+
+func (p *Placeholder) PatchUInt32(data uint32, endianness Endianness) error {
+	return p.patchUInt("PatchUInt32", uint64(data), Int32Size, endianness)
+}
+
+func (p *Placeholder) PatchInt64(data int64, endianness Endianness) error {
+	return p.patchInt("PatchInt64", data, Int64Size, endianness)
+}
+
+func (p *Placeholder) PatchUInt64(data uint64, endianness Endianness) error {
+	return p.patchUInt("PatchUInt64", data, Int64Size, endianness)
+}
+
+// PatchUIntSize fills the Placeholder with data as an unsigned integer as wide as the Placeholder, see WriteUIntSize.
+func (p *Placeholder) PatchUIntSize(data int64, endianness Endianness) error {
+	return p.patchUInt("PatchUIntSize", uint64(data), p.width, endianness)
+}
+
+// PatchIntSize is the signed counterpart of PatchUIntSize.
+func (p *Placeholder) PatchIntSize(data int64, endianness Endianness) error {
+	return p.patchInt("PatchIntSize", data, p.width, endianness)
+}
+
+// The Patch methods fail with ErrInvalidLength when the value's width isn't the reserved width, and with ErrReservation when the
+// Placeholder has already been patched.
+func (p *Placeholder) patchUInt(op string, data uint64, size uint8, endianness Endianness) error {
+	if err := p.check(op, size); err != nil {
+		return err
+	}
+	var buf [Int64Size]byte
+	_bytes, err := appendUIntSize(op, p.offset, buf[:0], data, size, endianness)
+	if err != nil {
+		return err
+	}
+	return p.fill(op, _bytes)
+}
+
+func (p *Placeholder) patchInt(op string, data int64, size uint8, endianness Endianness) error {
+	if err := p.check(op, size); err != nil {
+		return err
+	}
+	var buf [Int64Size]byte
+	_bytes, err := appendIntSize(op, p.offset, buf[:0], data, size, endianness)
+	if err != nil {
+		return err
+	}
+	return p.fill(op, _bytes)
+}
+
+func (p *Placeholder) check(op string, size uint8) error {
+	if p.done {
+		return &Error{Op: op, Offset: p.offset, Kind: ErrReservation}
+	}
+	if size != p.width {
+		return &Error{Op: op, Offset: p.offset, Expected: int64(p.width), Actual: int64(size), Kind: ErrInvalidLength}
+	}
+	return nil
+}
+
+// fill copies the encoded value over the reserved bytes, they're still in Buffer since nothing is flushed while p is pending.
+func (p *Placeholder) fill(op string, _bytes []byte) error {
+	w := p.w
+	copy(w.Buffer.Bytes()[p.offset-w.flushed:], _bytes)
+	p.done = true
+	for i, reserved := range w.reserved {
+		if reserved == p {
+			w.reserved = append(w.reserved[:i], w.reserved[i+1:]...)
+			break
+		}
+	}
+	return w.flushIfFull(op)
+}
+
+// holding reports whether there's output that will still be patched, which keeps the Writer from flushing.
+func (w *Writer) holding() bool {
+	return len(w.reserved) > 0 || len(w.sections) > 0
+}
+
+type section struct {
+	prefix LengthPrefix
+	// start is where the section's data starts, placeholder is where the length goes for a LengthFixed prefix. Any other prefix is
+	// inserted in front of the data by EndSection since its width isn't known up front.
+	start       int64
+	placeholder *Placeholder
+}
+
+// BeginSection starts a section whose length, in bytes, is written in front of it encoded as prefix once EndSection is called, so a
+// message body doesn't have to be encoded separately to learn its length. Sections can be nested, each EndSection ends the innermost
+// one. Nothing is flushed until every section has ended.
+func (w *Writer) BeginSection(prefix LengthPrefix) error {
+	const op = "BeginSection"
+	s := section{prefix: prefix}
+	switch prefix.Encoding {
+	case LengthFixed:
+		placeholder, err := w.reserve(op, int(prefix.Size))
+		if err != nil {
+			return err
+		}
+		s.placeholder = placeholder
+	case LengthVarInt, LengthRRS:
+		if w.err != nil {
+			return w.fail(op, nil, 0, 0, w.err)
+		}
+		w.bitIndex = 0
+	default:
+		return w.fail(op, ErrInvalidLength, 0, int64(prefix.Encoding), nil)
+	}
+	s.start = w.Offset()
+	w.sections = append(w.sections, s)
+	return nil
+}
+
+// EndSection writes the length of the innermost section, it fails with ErrReservation if there's no section to end. A length that
+// doesn't fit in the prefix leaves the output broken, so every call after that fails too.
+func (w *Writer) EndSection() error {
+	const op = "EndSection"
+	if len(w.sections) == 0 {
+		return w.fail(op, ErrReservation, 0, 0, nil)
+	}
+	s := w.sections[len(w.sections)-1]
+	w.sections = w.sections[:len(w.sections)-1]
+	// Booleans written after the section must not end up packed into its last byte
+	w.bitIndex = 0
+	length := int(w.Offset() - s.start)
+
+	if s.placeholder != nil {
+		var buf [Int64Size]byte
+		_bytes, err := appendLength(op, s.placeholder.offset, buf[:0], s.prefix, length, false)
+		if err != nil {
+			w.err = err
+			return err
+		}
+		return s.placeholder.fill(op, _bytes)
+	}
+
+	var buf [binary.MaxVarintLen64]byte
+	header, err := appendLength(op, s.start, buf[:0], s.prefix, length, false)
+	if err != nil {
+		w.err = err
+		return err
+	}
+	// Make room at the end and move the section's data up to fit the header in front of it
+	w.Buffer.Write(header)
+	_bytes := w.Buffer.Bytes()
+	i := int(s.start - w.flushed)
+	copy(_bytes[i+len(header):], _bytes[i:len(_bytes)-len(header)])
+	copy(_bytes[i:], header)
+	for _, p := range w.reserved {
+		if p.offset >= s.start {
+			p.offset += int64(len(header))
+		}
+	}
+	return w.flushIfFull(op)
+}
@@ -0,0 +1,174 @@
+package bytestream
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriter_Reserve(t *testing.T) {
+	w := NewWriter()
+	w.WriteInt8(0x01)
+	count, err := w.Reserve(Int24Size)
+	if err != nil {
+		t.Fatalf("Writer.Reserve() error = %v", err)
+	}
+	w.WriteInt16(0x0203, BigEndian)
+	if err := count.PatchUInt24(0xABCDEF, LittleEndian); err != nil {
+		t.Fatalf("Placeholder.PatchUInt24() error = %v", err)
+	}
+	want := []byte{0x01, 0xEF, 0xCD, 0xAB, 0x02, 0x03}
+	if got := w.Buffer.Bytes(); !bytes.Equal(got, want) {
+		t.Errorf("Writer.Buffer = %v, want %v", got, want)
+	}
+	if count.Offset() != 1 || count.Width() != Int24Size {
+		t.Errorf("Placeholder offset %v width %v, want offset 1 width 3", count.Offset(), count.Width())
+	}
+}
+
+func TestPlaceholder_Errors(t *testing.T) {
+	w := NewWriter()
+	if _, err := w.Reserve(9); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Writer.Reserve(9) error = %v, want %v", err, ErrInvalidLength)
+	}
+	p, _ := w.Reserve(Int32Size)
+	if err := p.PatchInt16(1, BigEndian); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Placeholder.PatchInt16() on 4 bytes error = %v, want %v", err, ErrInvalidLength)
+	}
+	if err := p.PatchIntSize(-1, BigEndian); err != nil {
+		t.Fatalf("Placeholder.PatchIntSize() error = %v", err)
+	}
+	if err := p.PatchInt32(1, BigEndian); !errors.Is(err, ErrReservation) {
+		t.Errorf("Placeholder.PatchInt32() twice error = %v, want %v", err, ErrReservation)
+	}
+	b, _ := w.Reserve(ByteSize)
+	var e *Error
+	if err := b.PatchIntSize(200, BigEndian); !errors.Is(err, ErrOverflow) || !errors.As(err, &e) || e.Offset != 4 {
+		t.Errorf("Placeholder.PatchIntSize(200) error = %v, want %v at offset 4", err, ErrOverflow)
+	}
+	if err := w.EndSection(); !errors.Is(err, ErrReservation) {
+		t.Errorf("Writer.EndSection() without a section error = %v, want %v", err, ErrReservation)
+	}
+}
+
+func TestWriter_ReserveHoldsFlush(t *testing.T) {
+	var dst bytes.Buffer
+	w := NewWriterToSize(&dst, 2)
+	p, _ := w.Reserve(Int16Size)
+	w.WriteInt32(1, BigEndian)
+	if dst.Len() != 0 {
+		t.Fatalf("flushed %v bytes with a pending placeholder", dst.Len())
+	}
+	if err := w.Flush(); !errors.Is(err, ErrReservation) {
+		t.Errorf("Writer.Flush() error = %v, want %v", err, ErrReservation)
+	}
+	if err := p.PatchUInt16(0x0102, BigEndian); err != nil {
+		t.Fatalf("Placeholder.PatchUInt16() error = %v", err)
+	}
+	want := []byte{0x01, 0x02, 0x00, 0x00, 0x00, 0x01}
+	if !bytes.Equal(dst.Bytes(), want) {
+		t.Errorf("flushed %v, want %v", dst.Bytes(), want)
+	}
+}
+
+func TestWriter_Section(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w *Writer)
+		want  []byte
+	}{
+		{
+			name: "fixed",
+			write: func(w *Writer) {
+				w.BeginSection(CountInt32)
+				w.WriteInt16(1, BigEndian)
+				w.EndSection()
+			},
+			want: []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x01},
+		},
+		{
+			name: "vint",
+			write: func(w *Writer) {
+				w.WriteInt8(0x7F)
+				w.BeginSection(CountVInt)
+				w.WriteBytes(make([]byte, 64))
+				w.EndSection()
+				w.WriteInt8(0x7E)
+			},
+			want: append(append([]byte{0x7F, 0x80, 0x01}, make([]byte, 64)...), 0x7E),
+		},
+		{
+			name: "nested",
+			write: func(w *Writer) {
+				w.BeginSection(CountUVarInt)
+				w.BeginSection(CountInt16)
+				w.BeginSection(CountByte)
+				w.WriteInt8(0x01)
+				w.EndSection()
+				w.EndSection()
+				w.WriteInt8(0x02)
+				w.EndSection()
+			},
+			want: []byte{0x05, 0x00, 0x02, 0x01, 0x01, 0x02},
+		},
+		{
+			name: "placeholder moved by a header",
+			write: func(w *Writer) {
+				w.BeginSection(CountUVarInt)
+				p, _ := w.Reserve(Int16Size)
+				w.WriteInt8(0x03)
+				w.EndSection()
+				p.PatchUInt16(0xBEEF, BigEndian)
+			},
+			want: []byte{0x03, 0xBE, 0xEF, 0x03},
+		},
+		{
+			name: "booleans",
+			write: func(w *Writer) {
+				w.BeginSection(CountByte)
+				w.WriteBoolean(true)
+				w.EndSection()
+				w.WriteBoolean(true)
+			},
+			want: []byte{0x01, 0x01, 0x01},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter()
+			tt.write(w)
+			if got := w.Buffer.Bytes(); !bytes.Equal(got, tt.want) {
+				t.Errorf("Writer.Buffer = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_SectionFlush(t *testing.T) {
+	var dst bytes.Buffer
+	w := NewWriterToSize(&dst, 1)
+	w.BeginSection(CountVInt)
+	w.WriteString("hi")
+	if dst.Len() != 0 {
+		t.Fatalf("flushed %v bytes with an open section", dst.Len())
+	}
+	if err := w.EndSection(); err != nil {
+		t.Fatalf("Writer.EndSection() error = %v", err)
+	}
+	want := []byte{0x06, 0x00, 0x00, 0x00, 0x02, 'h', 'i'}
+	if !bytes.Equal(dst.Bytes(), want) {
+		t.Errorf("flushed %v, want %v", dst.Bytes(), want)
+	}
+}
+
+func TestWriter_SectionOverflow(t *testing.T) {
+	w := NewWriter()
+	w.BeginSection(CountByte)
+	w.WriteBytes(make([]byte, 256))
+	if err := w.EndSection(); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("Writer.EndSection() error = %v, want %v", err, ErrOverflow)
+	}
+	if err := w.WriteInt8(1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Writer.WriteInt8() after a broken section error = %v, want %v", err, ErrOverflow)
+	}
+}
@@ -0,0 +1,74 @@
+package bytestream
+
+import "io"
+
+// Sub consumes the next n bytes and returns a Reader confined to them, for a section that's a length followed by that many bytes of
+// structure. A decoder reading the section through the sub-reader can't read past its end into whatever follows, and the parent is
+// already positioned after the section no matter how much of it the decoder reads. The sub-reader's offsets (and errors) are offsets
+// in the parent's stream, it has the parent's options and byte order and what it allocates is taken out of the parent's budget.
+func (r *Reader) Sub(n int) (*Reader, error) {
+	const op = "Sub"
+	start := r.offset
+	var data []byte
+	var err error
+	if r.src == nil {
+		// Nothing is ever written to an in-memory Reader's buffer, so the section can be shared instead of copied
+		if n < 0 {
+			return nil, r.fail(op, ErrInvalidLength, 0, int64(n), nil)
+		}
+		data, err = r.next(op, n)
+	} else {
+		data, err = r.readBytes(op, n)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sub := NewReader(data[:n:n])
+	sub.offset, sub.originOffset = start, start
+	sub.opts, sub.order = r.opts, r.order
+	sub.budget = r
+	if r.budget != nil {
+		sub.budget = r.budget
+	}
+	return sub, nil
+}
+
+// ExpectEOF checks that everything has been read, it fails with ErrTrailingData (Actual is how many bytes are left) if it hasn't.
+// A Reader backed by an io.Reader reads ahead to find out.
+func (r *Reader) ExpectEOF() error {
+	const op = "ExpectEOF"
+	if r.src != nil {
+		r.fill(1)
+	}
+	if left := r.Reader.Len(); left > 0 {
+		return r.fail(op, ErrTrailingData, 0, int64(left), nil)
+	}
+	if r.srcErr != nil && r.srcErr != io.EOF {
+		return r.fail(op, nil, 0, 0, r.srcErr)
+	}
+	return nil
+}
+
+// SkipTrailing is the lenient counterpart of ExpectEOF, it skips whatever is left and returns how many bytes that was so a decoder can
+// tolerate (and still log) fields added by a newer version of the protocol.
+func (r *Reader) SkipTrailing() (int64, error) {
+	const op = "SkipTrailing"
+	var skipped int64
+	for {
+		if r.src != nil && r.Reader.Len() == 0 && r.srcErr == nil {
+			r.fill(1)
+		}
+		left := r.Reader.Len()
+		if left == 0 {
+			break
+		}
+		if _, err := r.next(op, left); err != nil {
+			return skipped, err
+		}
+		skipped += int64(left)
+	}
+	if r.srcErr != nil && r.srcErr != io.EOF {
+		return skipped, r.fail(op, nil, 0, 0, r.srcErr)
+	}
+	return skipped, nil
+}
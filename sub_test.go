@@ -0,0 +1,124 @@
+package bytestream
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReader_Sub(t *testing.T) {
+	data := []byte{0x00, 0x00, 0x00, 0x03, 0x00, 0x01, 0x02, 0x7F}
+	tests := []struct {
+		name string
+		r    *Reader
+	}{
+		{name: "in memory", r: NewReader(data)},
+		{name: "streamed", r: NewReaderFromSize(iotest.OneByteReader(bytes.NewReader(data)), 2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			length, _ := tt.r.ReadInt32(BigEndian)
+			sub, err := tt.r.Sub(int(length))
+			if err != nil {
+				t.Fatalf("Reader.Sub() error = %v", err)
+			}
+			if got, _ := sub.ReadInt16(BigEndian); got != 1 {
+				t.Errorf("sub.ReadInt16() = %v, want 1", got)
+			}
+			// Reading past the section fails instead of eating into the next field
+			_, err = sub.ReadInt16(BigEndian)
+			var e *Error
+			if !errors.Is(err, ErrShortRead) || !errors.As(err, &e) || e.Offset != 6 {
+				t.Errorf("sub.ReadInt16() past the end error = %v, want %v at offset 6", err, ErrShortRead)
+			}
+			if got, _ := tt.r.ReadInt8(); got != 0x7F {
+				t.Errorf("Reader.ReadInt8() after the section = %v, want 0x7F", got)
+			}
+		})
+	}
+}
+
+func TestReader_SubErrors(t *testing.T) {
+	r := NewReader([]byte{0x01, 0x02})
+	if _, err := r.Sub(-1); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("Reader.Sub(-1) error = %v, want %v", err, ErrInvalidLength)
+	}
+	if _, err := r.Sub(3); !errors.Is(err, ErrShortRead) {
+		t.Errorf("Reader.Sub(3) error = %v, want %v", err, ErrShortRead)
+	}
+	r = NewReaderFrom(bytes.NewReader([]byte{0x01, 0x02}))
+	r.SetOptions(ReaderOptions{MaxBytesLength: 1})
+	if _, err := r.Sub(2); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Reader.Sub(2) over MaxBytesLength error = %v, want %v", err, ErrLimitExceeded)
+	}
+}
+
+func TestReader_SubBudget(t *testing.T) {
+	r := NewReader(make([]byte, 240))
+	r.SetOptions(ReaderOptions{AllocationBudget: 100})
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		var sub *Reader
+		if sub, err = r.Sub(80); err == nil {
+			// A nested sub-reader spends the same budget
+			var nested *Reader
+			if nested, err = sub.Sub(80); err == nil {
+				_, err = nested.ReadBytes(80)
+			}
+		}
+	}
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("reading 3 sub-readers of 80 bytes with a budget of 100 error = %v, want %v", err, ErrLimitExceeded)
+	}
+	if r.allocated != 80 {
+		t.Errorf("parent allocated %v, want 80", r.allocated)
+	}
+}
+
+func TestReader_ExpectEOF(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       *Reader
+		read    int
+		wantErr error
+	}{
+		{name: "consumed", r: NewReader([]byte{0x01, 0x02}), read: 2},
+		{name: "trailing", r: NewReader([]byte{0x01, 0x02, 0x03}), read: 1, wantErr: ErrTrailingData},
+		{name: "streamed consumed", r: NewReaderFromSize(bytes.NewReader([]byte{0x01, 0x02}), 1), read: 2},
+		{name: "streamed trailing", r: NewReaderFromSize(bytes.NewReader([]byte{0x01, 0x02}), 1), read: 1, wantErr: ErrTrailingData},
+		{name: "streamed failure", r: NewReaderFrom(iotest.TimeoutReader(bytes.NewReader([]byte{0x01}))), read: 1, wantErr: iotest.ErrTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.r.Skip(tt.read)
+			err := tt.r.ExpectEOF()
+			if tt.wantErr == nil && err != nil || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Reader.ExpectEOF() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReader_SkipTrailing(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *Reader
+		want int64
+	}{
+		{name: "in memory", r: NewReader([]byte{0x01, 0x02, 0x03, 0x04}), want: 3},
+		{name: "streamed", r: NewReaderFromSize(iotest.HalfReader(bytes.NewReader([]byte{0x01, 0x02, 0x03, 0x04})), 2), want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.r.ReadInt8()
+			got, err := tt.r.SkipTrailing()
+			if err != nil || got != tt.want {
+				t.Fatalf("Reader.SkipTrailing() = %v, %v, want %v", got, err, tt.want)
+			}
+			if err := tt.r.ExpectEOF(); err != nil {
+				t.Errorf("Reader.ExpectEOF() after SkipTrailing error = %v", err)
+			}
+		})
+	}
+}
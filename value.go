@@ -0,0 +1,139 @@
+package bytestream
+
+import (
+	"encoding"
+	"reflect"
+)
+
+// An Encodable is a type that knows how to write itself to a Writer.
+type Encodable interface {
+	Encode(w *Writer) error
+}
+
+// A Decodable is a type that knows how to read itself from a Reader.
+type Decodable interface {
+	Decode(r *Reader) error
+}
+
+// WriteValue writes v by calling its Encode method.
+func (w *Writer) WriteValue(v Encodable) error {
+	return v.Encode(w)
+}
+
+// ReadValue reads into v by calling its Decode method.
+func (r *Reader) ReadValue(v Decodable) error {
+	return v.Decode(r)
+}
+
+// WriteOptionalValue writes a bool saying whether v is there followed by v itself, a nil interface or nil pointer is written as absent.
+func (w *Writer) WriteOptionalValue(v Encodable) error {
+	present := !isNil(v)
+	if err := w.writeByte("WriteOptionalValue", boolByte(present)); err != nil {
+		return err
+	}
+	if !present {
+		return nil
+	}
+	return v.Encode(w)
+}
+
+// ReadOptionalValue reads what WriteOptionalValue writes, v is only decoded into when present is true.
+func (r *Reader) ReadOptionalValue(v Decodable) (present bool, err error) {
+	_byte, err := r.readByte("ReadOptionalValue")
+	if err != nil {
+		return false, err
+	}
+	if _byte == 0x00 {
+		return false, nil
+	}
+	return true, v.Decode(r)
+}
+
+// WriteBinary writes the output of v.MarshalBinary with an int32 length prefix, so types from the standard library (time.Time,
+// url.URL...) or anything else implementing encoding.BinaryMarshaler can be sent.
+func (w *Writer) WriteBinary(v encoding.BinaryMarshaler) error {
+	data, err := v.MarshalBinary()
+	if err != nil {
+		return w.fail("WriteBinary", nil, 0, 0, err)
+	}
+	if err := w.writeInt("WriteBinary", int64(len(data)), Int32Size, BigEndian); err != nil {
+		return err
+	}
+	return w.write("WriteBinary", data)
+}
+
+// ReadBinary reads what WriteBinary writes and hands it to v.UnmarshalBinary.
+func (r *Reader) ReadBinary(v encoding.BinaryUnmarshaler) error {
+	length, err := r.readInt("ReadBinary", Int32Size, BigEndian)
+	if err != nil {
+		return err
+	}
+	// UnmarshalBinary is allowed to keep data around so it gets its own copy
+	data, err := r.readBytes("ReadBinary", int(length))
+	if err != nil {
+		return err
+	}
+	if err := v.UnmarshalBinary(data); err != nil {
+		return r.fail("ReadBinary", nil, 0, 0, err)
+	}
+	return nil
+}
+
+// MarshalBinary encodes v on its own, it makes implementing encoding.BinaryMarshaler for an Encodable a one liner.
+func MarshalBinary(v Encodable) ([]byte, error) {
+	w := NewWriter()
+	if err := v.Encode(w); err != nil {
+		return nil, err
+	}
+	return w.Buffer.Bytes(), nil
+}
+
+// UnmarshalBinary decodes data into v, see MarshalBinary.
+func UnmarshalBinary(data []byte, v Decodable) error {
+	return v.Decode(NewReader(data))
+}
+
+// WriteValues writes an int32 count followed by every value, a nil slice is written as a count of -1.
+func WriteValues[T Encodable](w *Writer, values []T) error {
+	return WriteSlice(w, CountInt32, values, func(w *Writer, v T) error { return v.Encode(w) })
+}
+
+// ReadValues reads what WriteValues writes, a count of -1 gives back a nil slice. T is the value type and *T has to be Decodable,
+// e.g. ReadValues[Player](r).
+func ReadValues[T any, PT interface {
+	*T
+	Decodable
+}](r *Reader) ([]T, error) {
+	return ReadSlice(r, CountInt32, 0, func(r *Reader) (T, error) {
+		var v T
+		err := PT(&v).Decode(r)
+		return v, err
+	})
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 0x01
+	}
+	return 0x00
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// isNil also catches a nil pointer stored in a non-nil interface.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package bytestream
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type point struct {
+	X, Y int16
+}
+
+func (p *point) Encode(w *Writer) error {
+	if err := w.WriteInt16(p.X, BigEndian); err != nil {
+		return err
+	}
+	return w.WriteInt16(p.Y, BigEndian)
+}
+
+func (p *point) Decode(r *Reader) error {
+	var err error
+	if p.X, err = r.ReadInt16(BigEndian); err != nil {
+		return err
+	}
+	p.Y, err = r.ReadInt16(BigEndian)
+	return err
+}
+
+func (p *point) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(p)
+}
+
+func (p *point) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, p)
+}
+
+func TestReader_ReadValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    point
+		wantErr bool
+	}{
+		{name: "nil", data: []byte{}, want: point{}, wantErr: true},
+		{name: "origin", data: []byte{0x00, 0x00, 0x00, 0x00}, want: point{}, wantErr: false},
+		{name: "point", data: []byte{0x00, 0x01, 0xFF, 0xFE}, want: point{X: 1, Y: -2}, wantErr: false},
+		{name: "short", data: []byte{0x00, 0x01, 0xFF}, want: point{X: 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got point
+			if err := NewReader(tt.data).ReadValue(&got); (err != nil) != tt.wantErr {
+				t.Errorf("Reader.ReadValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Reader.ReadValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteValue(t *testing.T) {
+	w := NewWriter()
+	if err := w.WriteValue(&point{X: 1, Y: -2}); err != nil {
+		t.Fatalf("Writer.WriteValue() error = %v", err)
+	}
+	if want := []byte{0x00, 0x01, 0xFF, 0xFE}; !bytes.Equal(w.Buffer.Bytes(), want) {
+		t.Errorf("Writer.WriteValue() wrote %v, want %v", w.Buffer.Bytes(), want)
+	}
+}
+
+func TestWriter_WriteOptionalValue(t *testing.T) {
+	var missing *point
+	tests := []struct {
+		name  string
+		value Encodable
+		want  []byte
+	}{
+		{name: "nil", value: nil, want: []byte{0x00}},
+		{name: "nil pointer", value: missing, want: []byte{0x00}},
+		{name: "present", value: &point{X: 1, Y: 2}, want: []byte{0x01, 0x00, 0x01, 0x00, 0x02}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter()
+			if err := w.WriteOptionalValue(tt.value); err != nil {
+				t.Fatalf("Writer.WriteOptionalValue() error = %v", err)
+			}
+			if !bytes.Equal(w.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteOptionalValue() wrote %v, want %v", w.Buffer.Bytes(), tt.want)
+			}
+
+			var got point
+			present, err := NewReader(tt.want).ReadOptionalValue(&got)
+			if err != nil || present != (len(tt.want) > 1) {
+				t.Fatalf("Reader.ReadOptionalValue() = %v, %v", present, err)
+			}
+			if present && got != *tt.value.(*point) {
+				t.Errorf("Reader.ReadOptionalValue() = %v, want %v", got, tt.value)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteBinary(t *testing.T) {
+	when := time.Date(2022, 7, 1, 12, 30, 0, 0, time.UTC)
+	w := NewWriter()
+	if err := w.WriteBinary(when); err != nil {
+		t.Fatalf("Writer.WriteBinary() error = %v", err)
+	}
+	if err := w.WriteBinary(&point{X: 3, Y: 4}); err != nil {
+		t.Fatalf("Writer.WriteBinary() error = %v", err)
+	}
+
+	r := NewReader(w.Buffer.Bytes())
+	var gotTime time.Time
+	if err := r.ReadBinary(&gotTime); err != nil || !gotTime.Equal(when) {
+		t.Errorf("Reader.ReadBinary() = %v, %v, want %v", gotTime, err, when)
+	}
+	var gotPoint point
+	if err := r.ReadBinary(&gotPoint); err != nil || gotPoint != (point{X: 3, Y: 4}) {
+		t.Errorf("Reader.ReadBinary() = %v, %v, want %v", gotPoint, err, point{X: 3, Y: 4})
+	}
+
+	var e *Error
+	if err := NewReader([]byte{0x00, 0x00, 0x00, 0x01, 0xFF}).ReadBinary(&gotTime); !errors.As(err, &e) || e.Op != "ReadBinary" {
+		t.Errorf("Reader.ReadBinary() error = %v, want a ReadBinary *Error", err)
+	}
+}
+
+func TestReadValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []point
+		want    []byte
+		wantErr bool
+	}{
+		{name: "nil", values: nil, want: []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		{name: "empty", values: []point{}, want: []byte{0x00, 0x00, 0x00, 0x00}},
+		{name: "two", values: []point{{X: 1, Y: 2}, {X: -1, Y: -2}}, want: []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x01, 0x00, 0x02, 0xFF, 0xFF, 0xFF, 0xFE}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter()
+			var ptrs []*point
+			if tt.values != nil {
+				ptrs = []*point{}
+			}
+			for i := range tt.values {
+				ptrs = append(ptrs, &tt.values[i])
+			}
+			if err := WriteValues(w, ptrs); err != nil {
+				t.Fatalf("WriteValues() error = %v", err)
+			}
+			if !bytes.Equal(w.Buffer.Bytes(), tt.want) {
+				t.Errorf("WriteValues() wrote %v, want %v", w.Buffer.Bytes(), tt.want)
+			}
+			got, err := ReadValues[point](NewReader(tt.want))
+			if err != nil {
+				t.Fatalf("ReadValues() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.values) {
+				t.Errorf("ReadValues() = %v, want %v", got, tt.values)
+			}
+		})
+	}
+
+	if _, err := ReadValues[point](NewReader([]byte{0x7F, 0xFF, 0xFF, 0xFF, 0x00})); !errors.Is(err, ErrShortRead) {
+		t.Errorf("ReadValues() with a huge count error = %v, want %v", err, ErrShortRead)
+	}
+	if _, err := ReadValues[point](NewReader([]byte{0xFF, 0xFF, 0xFF, 0xFE})); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("ReadValues() with a negative count error = %v, want %v", err, ErrInvalidLength)
+	}
+}
+
+func TestMarshal_Values(t *testing.T) {
+	type Shape struct {
+		Name   string
+		Origin point
+		Corner *point
+		Points []point `bs:",len=byte"`
+		When   time.Time
+	}
+	want := Shape{
+		Name:   "square",
+		Origin: point{X: 1, Y: 1},
+		Corner: &point{X: 5, Y: 5},
+		Points: []point{{X: 1, Y: 5}, {X: 5, Y: 1}},
+		When:   time.Date(2022, 7, 1, 12, 30, 0, 0, time.UTC),
+	}
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var got Shape
+	if err := Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, want)
+	}
+
+	if _, err := Marshal(Shape{}); err == nil {
+		t.Errorf("Marshal() with a nil Corner error = nil, want an error")
+	}
+}
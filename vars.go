@@ -24,6 +24,8 @@ const (
 	Int24Size     = Int16Size + Int8Size
 	Int32Size     = Int16Size * 2
 	Int64Size     = Int32Size * 2
+	Float32Size   = Int32Size
+	Float64Size   = Int64Size
 	LongSize      = 8 // Not tested if this works on 32-bit machines...
 	LogicLongSize = LongSize
 	LongLongSize  = LongSize
@@ -2,312 +2,488 @@ package bytestream
 
 import (
 	"bytes"
-	"compress/zlib"
 	"encoding/binary"
-	"fmt"
+	"io"
+	"math"
+	"math/bits"
 )
 
+// DefaultWriteSize is how many bytes a Writer created with NewWriterTo buffers before flushing them.
+const DefaultWriteSize = 4096
+
 type Writer struct {
+	// Buffer holds everything written so far, for a Writer created with NewWriterTo it only holds what hasn't been flushed yet.
 	Buffer *bytes.Buffer
+
+	dst     io.Writer
+	size    int
+	err     error
+	flushed int64
+
+	// bitIndex is the next bit WriteBoolean sets in the last byte of Buffer, any other write starts over at 0.
+	bitIndex uint8
+
+	// reserved are the placeholders that haven't been patched yet and sections the ones that haven't ended, nothing is flushed
+	// while either is around.
+	reserved []*Placeholder
+	sections []section
+
+	// mustErr is the first error a MustWriter call ran into.
+	mustErr error
+
+	// order is what the short methods (Int16, Int32...) write with.
+	order Endianness
 }
 
 func NewWriter() *Writer {
 	return &Writer{Buffer: bytes.NewBuffer([]byte{})}
 }
 
-func (w *Writer) WriteBytes(bytes []byte) error {
-	n, err := w.Buffer.Write(bytes)
+// NewWriterTo returns a Writer that buffers what's written and sends it on to dst (a net.Conn, a file...) once DefaultWriteSize bytes are
+// buffered or Flush is called. Once a write to dst fails every later call returns that error.
+func NewWriterTo(dst io.Writer) *Writer {
+	return NewWriterToSize(dst, DefaultWriteSize)
+}
+
+// NewWriterToSize is like NewWriterTo but flushes once size bytes are buffered.
+func NewWriterToSize(dst io.Writer, size int) *Writer {
+	if size <= 0 {
+		size = DefaultWriteSize
+	}
+	return &Writer{Buffer: bytes.NewBuffer(make([]byte, 0, size)), dst: dst, size: size}
+}
+
+// Flush sends everything buffered to the underlying io.Writer, it does nothing for a Writer created with NewWriter.
+func (w *Writer) Flush() error {
+	return w.flush("Flush")
+}
+
+func (w *Writer) flush(op string) error {
+	if w.err != nil {
+		return w.fail(op, nil, 0, 0, w.err)
+	}
+	if w.dst == nil || w.Buffer.Len() == 0 {
+		return nil
+	}
+	if w.holding() {
+		return w.fail(op, ErrReservation, 0, int64(len(w.reserved)+len(w.sections)), nil)
+	}
+	w.bitIndex = 0
+	buffered := w.Buffer.Len()
+	n, err := w.dst.Write(w.Buffer.Bytes())
+	w.Buffer.Next(n)
+	w.flushed += int64(n)
+	if err == nil && w.Buffer.Len() > 0 {
+		err = io.ErrShortWrite
+	}
 	if err != nil {
-		return err
+		w.err = err
+		return w.fail(op, nil, int64(buffered), int64(n), err)
+	}
+	return nil
+}
+
+// Buffered returns how many bytes have been written but not flushed yet.
+func (w *Writer) Buffered() int {
+	return w.Buffer.Len()
+}
+
+// Offset returns how many bytes have been written so far, flushed or not.
+func (w *Writer) Offset() int64 {
+	return w.flushed + int64(w.Buffer.Len())
+}
+
+// write, writeString and writeByte are what every Write* method ends up calling, they take care of the error stickiness and flushing.
+func (w *Writer) write(op string, p []byte) error {
+	if w.err != nil {
+		return w.fail(op, nil, 0, 0, w.err)
+	}
+	w.bitIndex = 0
+	w.Buffer.Write(p)
+	return w.flushIfFull(op)
+}
+
+// buffer is the fast path of write for the fixed width writes to an in-memory Writer, small enough to be inlined. It reports false
+// when the write has to go through write instead: the Writer has an error to report, or it writes to an io.Writer and may have to flush.
+func (w *Writer) buffer(p []byte) bool {
+	if w.err != nil || w.dst != nil {
+		return false
+	}
+	w.bitIndex = 0
+	w.Buffer.Write(p)
+	return true
+}
+
+func (w *Writer) writeString(op string, s string) error {
+	if w.err != nil {
+		return w.fail(op, nil, 0, 0, w.err)
+	}
+	w.bitIndex = 0
+	w.Buffer.WriteString(s)
+	return w.flushIfFull(op)
+}
+
+func (w *Writer) writeByte(op string, b byte) error {
+	if w.err != nil {
+		return w.fail(op, nil, 0, 0, w.err)
 	}
-	if n != len(bytes) {
-		return fmt.Errorf("invalid number of bytes written! Wrote: " + fmt.Sprint(n) + " Expected: " + fmt.Sprint(len(bytes)))
+	w.bitIndex = 0
+	w.Buffer.WriteByte(b)
+	return w.flushIfFull(op)
+}
+
+func (w *Writer) flushIfFull(op string) error {
+	// The byte booleans are being packed into has to stay in Buffer until it's full, and so does anything that will still be patched
+	if w.dst != nil && w.Buffer.Len() >= w.size && w.bitIndex == 0 && !w.holding() {
+		return w.flush(op)
 	}
 	return nil
 }
 
+func (w *Writer) WriteBytes(bytes []byte) error {
+	return w.write("WriteBytes", bytes)
+}
+
 func (w *Writer) WriteBool(data bool, count int8) error {
 	if !data {
-		return w.Buffer.WriteByte(0x00)
+		return w.writeByte("WriteBool", 0x00)
+	}
+	return w.writeByte("WriteBool", byte(count))
+}
+
+// WriteBoolean writes a bool the way the game's ByteStream does, up to 8 booleans in a row are packed into one byte (lowest bit first).
+// Writing anything else in between (or flushing) starts a new byte for the next boolean.
+func (w *Writer) WriteBoolean(data bool) error {
+	if w.err != nil {
+		return w.fail("WriteBoolean", nil, 0, 0, w.err)
+	}
+	if w.bitIndex == 0 {
+		w.Buffer.WriteByte(0x00)
 	}
-	return w.Buffer.WriteByte(byte(count))
+	if data {
+		packed := w.Buffer.Bytes()
+		packed[len(packed)-1] |= 1 << w.bitIndex
+	}
+	w.bitIndex = (w.bitIndex + 1) & 7
+	return w.flushIfFull("WriteBoolean")
 }
 
 func (w *Writer) WriteInt8(data int8) error {
 	// An int8 is effectively a byte
-	return w.Buffer.WriteByte(byte(data))
+	return w.writeByte("WriteInt8", byte(data))
 }
 
 func (w *Writer) WriteUInt8(data uint8) error {
-	return w.Buffer.WriteByte(byte(data))
+	return w.writeByte("WriteUInt8", byte(data))
 }
 
+// The fixed width writes encode straight into a stack array instead of going through writeUInt, which has to handle any size, and
+// skip write when they can.
+
 func (w *Writer) WriteInt16(data int16, endianness Endianness) error {
-	switch endianness {
-	case BigEndian:
-		return binary.Write(w.Buffer, binary.BigEndian, data)
-	case LittleEndian:
-		return binary.Write(w.Buffer, binary.LittleEndian, data)
-	default:
-		return fmt.Errorf("invalid endianness")
+	var buf [Int16Size]byte
+	endianness.PutUint16(buf[:], uint16(data))
+	if w.buffer(buf[:]) {
+		return nil
 	}
+	return w.write("WriteInt16", buf[:])
 }
 
 func (w *Writer) WriteUInt16(data uint16, endianness Endianness) error {
-	switch endianness {
-	case BigEndian:
-		return binary.Write(w.Buffer, binary.BigEndian, data)
-	case LittleEndian:
-		return binary.Write(w.Buffer, binary.LittleEndian, data)
-	default:
-		return fmt.Errorf("invalid endianness")
+	var buf [Int16Size]byte
+	endianness.PutUint16(buf[:], data)
+	if w.buffer(buf[:]) {
+		return nil
 	}
+	return w.write("WriteUInt16", buf[:])
 }
 
 func (w *Writer) WriteInt24(data int32, endianness Endianness) error {
-	if data > 0x7FFFFF {
-		return fmt.Errorf("int24 overflow")
-	}
-
-	switch endianness {
-	case BigEndian:
-		w.Buffer.WriteByte(byte(data >> 16))
-		w.Buffer.WriteByte(byte(data >> 8))
-		w.Buffer.WriteByte(byte(data))
-	case LittleEndian:
-		w.Buffer.WriteByte(byte(data))
-		w.Buffer.WriteByte(byte(data >> 8))
-		w.Buffer.WriteByte(byte(data >> 16))
-	default:
-		return fmt.Errorf("invalid endianness")
+	if data < -1<<23 || data > 1<<23-1 {
+		// writeInt reports the overflow
+		return w.writeInt("WriteInt24", int64(data), Int24Size, endianness)
 	}
-	return nil
+	var buf [Int24Size]byte
+	endianness.putUint24(buf[:], uint32(data))
+	if w.buffer(buf[:]) {
+		return nil
+	}
+	return w.write("WriteInt24", buf[:])
 }
 
 func (w *Writer) WriteUInt24(data uint32, endianness Endianness) error {
-	if data > 0xFFFFFF {
-		return fmt.Errorf("uint24 overflow")
-	}
-
-	switch endianness {
-	case BigEndian:
-		w.Buffer.WriteByte(byte(data >> 16))
-		w.Buffer.WriteByte(byte(data >> 8))
-		w.Buffer.WriteByte(byte(data))
-	case LittleEndian:
-		w.Buffer.WriteByte(byte(data))
-		w.Buffer.WriteByte(byte(data >> 8))
-		w.Buffer.WriteByte(byte(data >> 16))
-	default:
-		return fmt.Errorf("invalid endianness")
+	if data > 1<<24-1 {
+		return w.writeUInt("WriteUInt24", uint64(data), Int24Size, endianness)
 	}
-	return nil
+	var buf [Int24Size]byte
+	endianness.putUint24(buf[:], data)
+	if w.buffer(buf[:]) {
+		return nil
+	}
+	return w.write("WriteUInt24", buf[:])
 }
 
 func (w *Writer) WriteInt32(data int32, endianness Endianness) error {
-	switch endianness {
-	case BigEndian:
-		return binary.Write(w.Buffer, binary.BigEndian, data)
-	case LittleEndian:
-		return binary.Write(w.Buffer, binary.LittleEndian, data)
-	default:
-		return fmt.Errorf("invalid endianness")
+	var buf [Int32Size]byte
+	endianness.PutUint32(buf[:], uint32(data))
+	if w.buffer(buf[:]) {
+		return nil
 	}
+	return w.write("WriteInt32", buf[:])
 }
 
 func (w *Writer) WriteUInt32(data uint32, endianness Endianness) error {
-	switch endianness {
-	case BigEndian:
-		return binary.Write(w.Buffer, binary.BigEndian, data)
-	case LittleEndian:
-		return binary.Write(w.Buffer, binary.LittleEndian, data)
-	default:
-		return fmt.Errorf("invalid endianness")
+	var buf [Int32Size]byte
+	endianness.PutUint32(buf[:], data)
+	if w.buffer(buf[:]) {
+		return nil
 	}
+	return w.write("WriteUInt32", buf[:])
 }
 
 func (w *Writer) WriteInt64(data int64, endianness Endianness) error {
-	switch endianness {
-	case BigEndian:
-		return binary.Write(w.Buffer, binary.BigEndian, data)
-	case LittleEndian:
-		return binary.Write(w.Buffer, binary.LittleEndian, data)
-	default:
-		return fmt.Errorf("invalid endianness")
+	var buf [Int64Size]byte
+	endianness.PutUint64(buf[:], uint64(data))
+	if w.buffer(buf[:]) {
+		return nil
 	}
+	return w.write("WriteInt64", buf[:])
 }
 
 func (w *Writer) WriteUInt64(data uint64, endianness Endianness) error {
-	switch endianness {
-	case BigEndian:
-		return binary.Write(w.Buffer, binary.BigEndian, data)
-	case LittleEndian:
-		return binary.Write(w.Buffer, binary.LittleEndian, data)
-	default:
-		return fmt.Errorf("invalid endianness")
+	var buf [Int64Size]byte
+	endianness.PutUint64(buf[:], data)
+	if w.buffer(buf[:]) {
+		return nil
 	}
+	return w.write("WriteUInt64", buf[:])
 }
 
-func (w *Writer) WriteVarInt(data int64) error {
-	ux := uint64(data) << 1
-	if data < 0 {
-		ux = ^ux
+// WriteFloat32 writes data as an IEEE-754 single precision float, the bits are written as they are so NaN payloads are preserved.
+func (w *Writer) WriteFloat32(data float32, endianness Endianness) error {
+	var buf [Float32Size]byte
+	endianness.PutUint32(buf[:], math.Float32bits(data))
+	if w.buffer(buf[:]) {
+		return nil
 	}
-	return w.WriteUVarInt(ux)
+	return w.write("WriteFloat32", buf[:])
+}
+
+// WriteFloat64 writes data as an IEEE-754 double precision float, see WriteFloat32.
+func (w *Writer) WriteFloat64(data float64, endianness Endianness) error {
+	var buf [Float64Size]byte
+	endianness.PutUint64(buf[:], math.Float64bits(data))
+	if w.buffer(buf[:]) {
+		return nil
+	}
+	return w.write("WriteFloat64", buf[:])
+}
+
+func (w *Writer) WriteVarInt(data int64) error {
+	return w.writeVarInt("WriteVarInt", data)
+}
+
+func (w *Writer) writeVarInt(op string, data int64) error {
+	var buf [binary.MaxVarintLen64]byte
+	return w.write(op, appendVarInt(buf[:0], data))
 }
 
 func (w *Writer) WriteUVarInt(data uint64) error {
-	for data >= 0x80 {
-		w.Buffer.WriteByte(byte(data) | 0x80)
-		data >>= 7
+	return w.writeUVarInt("WriteUVarInt", data)
+}
+
+func (w *Writer) writeUVarInt(op string, data uint64) error {
+	var buf [binary.MaxVarintLen64]byte
+	return w.write(op, appendUVarInt(buf[:0], data))
+}
+
+// WriteRRSInt32 writes data as the game's VInt, see ReadRRSInt32.
+func (w *Writer) WriteRRSInt32(data int32) error {
+	return w.writeRRSInt32("WriteRRSInt32", data)
+}
+
+func (w *Writer) writeRRSInt32(op string, data int32) error {
+	var buf [binary.MaxVarintLen32]byte
+	return w.write(op, appendRRSInt32(buf[:0], data))
+}
+
+// WriteRRSLong writes a LogicLong as two VInts, high first.
+func (w *Writer) WriteRRSLong(data LogicLong) error {
+	err := w.writeRRSInt32("WriteRRSLong", data.High)
+	if err != nil {
+		return err
 	}
-	return w.Buffer.WriteByte(byte(data))
+	return w.writeRRSInt32("WriteRRSLong", data.Low)
 }
 
 func (w *Writer) WriteLong(data int64, endianness Endianness) error {
 	// A long is 4 bytes on a 32-bit machine and 8 bytes on a 64-bit machine.
 	if Is64Bit {
-		return w.WriteInt64(data, endianness)
+		return w.writeInt("WriteLong", data, Int64Size, endianness)
 	} else {
-		return w.WriteInt32(int32(data), endianness)
+		return w.writeInt("WriteLong", int64(int32(data)), Int32Size, endianness)
 	}
 }
 
 func (w *Writer) WriteUnsignedLong(data uint64, endianness Endianness) error {
 	// A long is 4 bytes on a 32-bit machine and 8 bytes on a 64-bit machine.
 	if Is64Bit {
-		return w.WriteUInt64(data, endianness)
+		return w.writeUInt("WriteUnsignedLong", data, Int64Size, endianness)
 	} else {
-		return w.WriteUInt32(uint32(data), endianness)
+		return w.writeUInt("WriteUnsignedLong", uint64(uint32(data)), Int32Size, endianness)
 	}
 }
 
 func (w *Writer) WriteLongLong(data int64, endianness Endianness) error {
 	// A long long is guaranteed to be 8 bytes
-	return w.WriteInt64(data, endianness)
+	return w.writeInt("WriteLongLong", data, Int64Size, endianness)
 }
 
 func (w *Writer) WriteUnsignedLongLong(data uint64, endianness Endianness) error {
 	// An unsigned long long is guaranteed to be 8 bytes
-	return w.WriteUInt64(data, endianness)
+	return w.writeUInt("WriteUnsignedLongLong", data, Int64Size, endianness)
 }
 
 func (w *Writer) WriteString(data string) error {
-	length := len(data)
-	err := w.WriteInt32(int32(length), BigEndian)
-	if err != nil {
+	if err := w.writeLength("WriteString", CountInt32, len(data), false); err != nil {
 		return err
 	}
-	n, err := w.Buffer.WriteString(data)
-	if err != nil {
-		return err
+	return w.writeString("WriteString", data)
+}
+
+// WriteNullableString is like WriteString but writes a null string (a length of -1) for nil.
+func (w *Writer) WriteNullableString(data *string) error {
+	const op = "WriteNullableString"
+	if data == nil {
+		return w.writeLength(op, CountInt32, 0, true)
 	}
-	if n != length {
-		return fmt.Errorf("invalid number of bytes written! Wrote: " + fmt.Sprint(n) + " Expected: " + fmt.Sprint(length))
+	if err := w.writeLength(op, CountInt32, len(*data), false); err != nil {
+		return err
 	}
-	return nil
+	return w.writeString(op, *data)
+}
+
+// WriteNullableBytes writes data with an int32 length prefix, nil is written as a length of -1.
+func (w *Writer) WriteNullableBytes(data []byte) error {
+	return w.writePrefixedBytes("WriteNullableBytes", data, CountInt32)
 }
 
 // This implementation writes the size of the string as a signed int of size bytesize, -1 will write 0xFFs for bytesize, and not write the string at all.
 func (w *Writer) WriteStringSize(data string, bytesize int8) error {
-	length := len(data)
-	switch bytesize {
-	case 1:
-		if length > 0x7F {
-			return fmt.Errorf("string size overflow")
-		}
-		err := w.WriteInt8(int8(length))
-		if err != nil {
-			return err
-		}
-	case 2:
-		if length > 0x7FFF {
-			return fmt.Errorf("string size overflow")
-		}
-		err := w.WriteInt16(int16(length), BigEndian)
-		if err != nil {
-			return err
-		}
-	case 3:
-		if length > 0x7FFFFF {
-			return fmt.Errorf("string size overflow")
-		}
-		err := w.WriteInt24(int32(length), BigEndian)
-		if err != nil {
-			return err
-		}
-	case 4:
-		if length > 0x7FFFFFFF {
-			return fmt.Errorf("string size overflow")
-		}
-		err := w.WriteInt32(int32(length), BigEndian)
-		if err != nil {
-			return err
-		}
-	case 8:
-		if length > 0x7FFFFFFFFFFFFFFF {
-			return fmt.Errorf("string size overflow")
-		}
-		err := w.WriteInt64(int64(length), BigEndian)
-		if err != nil {
-			return err
-		}
-	default:
-		return fmt.Errorf("unimplemented string byte size")
-	}
-	n, err := w.Buffer.WriteString(data)
-	if err != nil {
-		return err
+	if bytesize < 1 || bytesize > Int64Size {
+		return w.fail("WriteStringSize", ErrInvalidLength, Int64Size, int64(bytesize), nil)
 	}
-	if n != length {
-		return fmt.Errorf("invalid number of bytes written! Wrote: " + fmt.Sprint(n) + " Expected: " + fmt.Sprint(length))
+	p := LengthPrefix{Encoding: LengthFixed, Size: uint8(bytesize), Endianness: BigEndian, Sign: Signed}
+	if err := w.writeLength("WriteStringSize", p, len(data), false); err != nil {
+		return err
 	}
-	return nil
+	return w.writeString("WriteStringSize", data)
 }
 
 func (w *Writer) WriteCompressedString(data string) error {
-	decompressedLength := len(data)
-	intermediateBuffer := bytes.NewBuffer([]byte{})
-	zlibWriter := zlib.NewWriter(intermediateBuffer)
-	n, err := zlibWriter.Write([]byte(data))
+	return w.writeCompressed("WriteCompressedString", []byte(data), Zlib)
+}
+
+// WriteNullableCompressedString is like WriteCompressedString but writes a null string (a compressed size of -1) for nil.
+func (w *Writer) WriteNullableCompressedString(data *string) error {
+	if data == nil {
+		return w.writeInt("WriteNullableCompressedString", -1, Int32Size, BigEndian)
+	}
+	return w.writeCompressed("WriteNullableCompressedString", []byte(*data), Zlib)
+}
+
+// WriteCompressedStringWith is like WriteCompressedString but compresses with c instead of zlib.
+func (w *Writer) WriteCompressedStringWith(data string, c Compressor) error {
+	return w.writeCompressed("WriteCompressedStringWith", []byte(data), c)
+}
+
+// WriteCompressedBytes writes data the way WriteCompressedString writes strings, nil is written as null (a compressed size of -1).
+func (w *Writer) WriteCompressedBytes(data []byte) error {
+	return w.writeCompressedBytes("WriteCompressedBytes", data, Zlib)
+}
+
+// WriteCompressedBytesWith is like WriteCompressedBytes but compresses with c instead of zlib.
+func (w *Writer) WriteCompressedBytesWith(data []byte, c Compressor) error {
+	return w.writeCompressedBytes("WriteCompressedBytesWith", data, c)
+}
+
+func (w *Writer) writeCompressedBytes(op string, data []byte, c Compressor) error {
+	if data == nil {
+		return w.writeInt(op, -1, Int32Size, BigEndian)
+	}
+	return w.writeCompressed(op, data, c)
+}
+
+func (w *Writer) writeCompressed(op string, data []byte, c Compressor) error {
+	_bytes, err := appendCompressed(op, w.Offset(), nil, data, c)
 	if err != nil {
 		return err
 	}
-	err = zlibWriter.Close()
+	return w.write(op, _bytes)
+}
+
+func (w *Writer) WriteLogicLong(data LogicLong, endianness Endianness) error {
+	err := w.writeInt("WriteLogicLong", int64(data.High), Int32Size, endianness)
 	if err != nil {
 		return err
 	}
-	compressedBytes := intermediateBuffer.Bytes()
-	compressedLength := len(compressedBytes)
-	err = w.WriteInt32(int32(compressedLength), BigEndian) // pack compressed size as BE
+	return w.writeInt("WriteLogicLong", int64(data.Low), Int32Size, endianness)
+}
+
+// WriteUIntSize writes data as an unsigned integer that is size bytes wide, anything from 1 to 8 bytes works (so 40/48/56-bit ints are fine too).
+// data fails with ErrOverflow if it's negative, except at 8 bytes where its bits are written as they are (int64 of a uint64 round trips).
+func (w *Writer) WriteUIntSize(data int64, size uint8, endianness Endianness) error {
+	return w.writeUInt("WriteUIntSize", uint64(data), size, endianness)
+}
+
+// WriteIntSize is the signed counterpart of WriteUIntSize, data has to fit in a two's complement integer that is size bytes wide.
+func (w *Writer) WriteIntSize(data int64, size uint8, endianness Endianness) error {
+	return w.writeInt("WriteIntSize", data, size, endianness)
+}
+
+// WriteInt writes value as an integer that is size bytes wide (1 to 8), signed or unsigned as sign says, for code that only learns the
+// encoding at runtime. A value that doesn't fit fails with ErrOverflow, a negative value never fits an unsigned integer.
+func (w *Writer) WriteInt(value int64, size uint8, sign Sign, endianness Endianness) error {
+	return w.writeIntSign("WriteInt", value, size, sign, endianness)
+}
+
+func (w *Writer) writeIntSign(op string, value int64, size uint8, sign Sign, endianness Endianness) error {
+	var buf [Int64Size]byte
+	_bytes, err := appendIntSign(op, w.Offset(), buf[:0], value, size, sign, endianness)
 	if err != nil {
 		return err
 	}
-	err = w.WriteInt32(int32(decompressedLength), LittleEndian) // pack uncompressed size as LE
+	return w.write(op, _bytes)
+}
+
+// writeUInt and writeInt are the shared path every fixed width integer write goes through.
+func (w *Writer) writeUInt(op string, data uint64, size uint8, endianness Endianness) error {
+	// A fixed size array stays on the stack, so writing a primitive never allocates
+	var buf [Int64Size]byte
+	_bytes, err := appendUIntSize(op, w.Offset(), buf[:0], data, size, endianness)
 	if err != nil {
 		return err
 	}
-	w.Buffer.Write(compressedBytes) // write compressed data
-	if n != decompressedLength {
-		return fmt.Errorf("invalid number of bytes written! Wrote: " + fmt.Sprint(n) + " Expected: " + fmt.Sprint(decompressedLength))
-	}
-	return nil
+	return w.write(op, _bytes)
 }
 
-// func (w *Writer) WriteLogicLong(tag string, endianness Endianness) error {
-// 	Low, High := TagToID(tag)
-// 	err := w.WriteInt32(int32(Low), endianness)
-// 	if err != nil {
-// 		return err
-// 	}
-// 	return w.WriteInt32(int32(High), endianness)
-// }
+func (w *Writer) writeInt(op string, data int64, size uint8, endianness Endianness) error {
+	var buf [Int64Size]byte
+	_bytes, err := appendIntSize(op, w.Offset(), buf[:0], data, size, endianness)
+	if err != nil {
+		return err
+	}
+	return w.write(op, _bytes)
+}
 
-// TODO allow writing any int with size from int8 to int64 (mostly allow 40/48/56...)
-func (w *Writer) WriteUIntSize(data int64, size uint8, endianness Endianness) error {
-	return nil
+// unsignedSize and signedSize are how many bytes data needs, they're reported in overflow errors.
+func unsignedSize(data uint64) int {
+	return (bits.Len64(data) + 7) / 8
 }
 
-func (w *Writer) WriteIntSize(data int64, size uint8, endianness Endianness) error {
-	return nil
+func signedSize(data int64) int {
+	if data < 0 {
+		data = ^data
+	}
+	return bits.Len64(uint64(data))/8 + 1
 }
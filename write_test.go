@@ -2,7 +2,11 @@ package bytestream
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"reflect"
 	"testing"
 )
 
@@ -749,3 +753,584 @@ func TestWriter_WriteCompressedString(t *testing.T) {
 		})
 	}
 }
+
+func TestWriter_WriteUIntSize(t *testing.T) {
+	type fields struct {
+		Buffer *bytes.Buffer
+	}
+	type args struct {
+		data       int64
+		size       uint8
+		endianness Endianness
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "40 bit BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0x0102030405, size: 5, endianness: BigEndian}, want: []byte{0x01, 0x02, 0x03, 0x04, 0x05}, wantErr: false},
+		{name: "48 bit BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0x010203040506, size: 6, endianness: BigEndian}, want: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, wantErr: false},
+		{name: "56 bit max BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0xFFFFFFFFFFFFFF, size: 7, endianness: BigEndian}, want: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+		{name: "64 bit max BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -1, size: 8, endianness: BigEndian}, want: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+		{name: "40 bit out of bounds BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0x010000000000, size: 5, endianness: BigEndian}, want: []byte{}, wantErr: true},
+		{name: "40 bit negative BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -1, size: 5, endianness: BigEndian}, want: []byte{}, wantErr: true},
+
+		{name: "40 bit LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0x0102030405, size: 5, endianness: LittleEndian}, want: []byte{0x05, 0x04, 0x03, 0x02, 0x01}, wantErr: false},
+		{name: "48 bit LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0x010203040506, size: 6, endianness: LittleEndian}, want: []byte{0x06, 0x05, 0x04, 0x03, 0x02, 0x01}, wantErr: false},
+		{name: "56 bit out of bounds LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0x0100000000000000, size: 7, endianness: LittleEndian}, want: []byte{}, wantErr: true},
+
+		{name: "size zero", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0, size: 0, endianness: BigEndian}, want: []byte{}, wantErr: true},
+		{name: "size nine", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0, size: 9, endianness: BigEndian}, want: []byte{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Writer{
+				Buffer: tt.fields.Buffer,
+			}
+			if err := w.WriteUIntSize(tt.args.data, tt.args.size, tt.args.endianness); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteUIntSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(tt.fields.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteUIntSize() wrote %v, want %v", tt.fields.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteIntSize(t *testing.T) {
+	type fields struct {
+		Buffer *bytes.Buffer
+	}
+	type args struct {
+		data       int64
+		size       uint8
+		endianness Endianness
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "40 bit one BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 1, size: 5, endianness: BigEndian}, want: []byte{0x00, 0x00, 0x00, 0x00, 0x01}, wantErr: false},
+		{name: "40 bit minus one BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -1, size: 5, endianness: BigEndian}, want: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+		{name: "40 bit max BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 549755813887, size: 5, endianness: BigEndian}, want: []byte{0x7F, 0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+		{name: "40 bit max minus BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -549755813888, size: 5, endianness: BigEndian}, want: []byte{0x80, 0x00, 0x00, 0x00, 0x00}, wantErr: false},
+		{name: "40 bit out of bounds BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 549755813888, size: 5, endianness: BigEndian}, want: []byte{}, wantErr: true},
+		{name: "40 bit out of bounds minus BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -549755813889, size: 5, endianness: BigEndian}, want: []byte{}, wantErr: true},
+		{name: "64 bit minus one BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -1, size: 8, endianness: BigEndian}, want: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+
+		{name: "48 bit minus two LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -2, size: 6, endianness: LittleEndian}, want: []byte{0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+		{name: "56 bit one LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 1, size: 7, endianness: LittleEndian}, want: []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, wantErr: false},
+
+		{name: "size zero", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0, size: 0, endianness: BigEndian}, want: []byte{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Writer{
+				Buffer: tt.fields.Buffer,
+			}
+			if err := w.WriteIntSize(tt.args.data, tt.args.size, tt.args.endianness); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteIntSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(tt.fields.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteIntSize() wrote %v, want %v", tt.fields.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteInt(t *testing.T) {
+	type fields struct {
+		Buffer *bytes.Buffer
+	}
+	type args struct {
+		value      int64
+		size       uint8
+		sign       Sign
+		endianness Endianness
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "signed byte", fields: fields{Buffer: new(bytes.Buffer)}, args: args{value: -1, size: 1, sign: Signed, endianness: BigEndian}, want: []byte{0xFF}, wantErr: false},
+		{name: "unsigned byte", fields: fields{Buffer: new(bytes.Buffer)}, args: args{value: 255, size: 1, sign: Unsigned, endianness: BigEndian}, want: []byte{0xFF}, wantErr: false},
+		{name: "signed byte out of bounds", fields: fields{Buffer: new(bytes.Buffer)}, args: args{value: 128, size: 1, sign: Signed, endianness: BigEndian}, want: []byte{}, wantErr: true},
+		{name: "unsigned byte out of bounds", fields: fields{Buffer: new(bytes.Buffer)}, args: args{value: 256, size: 1, sign: Unsigned, endianness: BigEndian}, want: []byte{}, wantErr: true},
+		{name: "unsigned negative", fields: fields{Buffer: new(bytes.Buffer)}, args: args{value: -1, size: 4, sign: Unsigned, endianness: BigEndian}, want: []byte{}, wantErr: true},
+		{name: "unsigned int24 LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{value: 0xABCDEF, size: 3, sign: Unsigned, endianness: LittleEndian}, want: []byte{0xEF, 0xCD, 0xAB}, wantErr: false},
+		{name: "signed int24 min LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{value: -8388608, size: 3, sign: Signed, endianness: LittleEndian}, want: []byte{0x00, 0x00, 0x80}, wantErr: false},
+		{name: "unsigned int32 max BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{value: 4294967295, size: 4, sign: Unsigned, endianness: BigEndian}, want: []byte{0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+		{name: "unsigned int64 max BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{value: math.MaxInt64, size: 8, sign: Unsigned, endianness: BigEndian}, want: []byte{0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+		{name: "size nine", fields: fields{Buffer: new(bytes.Buffer)}, args: args{value: 0, size: 9, sign: Unsigned, endianness: BigEndian}, want: []byte{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Writer{
+				Buffer: tt.fields.Buffer,
+			}
+			if err := w.WriteInt(tt.args.value, tt.args.size, tt.args.sign, tt.args.endianness); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteInt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(tt.fields.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteInt() wrote %v, want %v", tt.fields.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteLogicLong(t *testing.T) {
+	type fields struct {
+		Buffer *bytes.Buffer
+	}
+	type args struct {
+		data       LogicLong
+		endianness Endianness
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "zero BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: LogicLong{}, endianness: BigEndian}, want: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, wantErr: false},
+		{name: "tag BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: LogicLong{High: 63, Low: 72003}, endianness: BigEndian}, want: []byte{0x00, 0x00, 0x00, 0x3F, 0x00, 0x01, 0x19, 0x43}, wantErr: false},
+		{name: "tag LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: LogicLong{High: 63, Low: 72003}, endianness: LittleEndian}, want: []byte{0x3F, 0x00, 0x00, 0x00, 0x43, 0x19, 0x01, 0x00}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Writer{
+				Buffer: tt.fields.Buffer,
+			}
+			if err := w.WriteLogicLong(tt.args.data, tt.args.endianness); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteLogicLong() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(tt.fields.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteLogicLong() wrote %v, want %v", tt.fields.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+type failingWriter struct {
+	n   int
+	err error
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.n >= len(p) {
+		f.n -= len(p)
+		return len(p), nil
+	}
+	n := f.n
+	f.n = 0
+	return n, f.err
+}
+
+func TestNewWriterTo(t *testing.T) {
+	dst := new(bytes.Buffer)
+	w := NewWriterToSize(dst, 4)
+	if err := w.WriteInt16(1, BigEndian); err != nil {
+		t.Fatalf("Writer.WriteInt16() error = %v", err)
+	}
+	if dst.Len() != 0 || w.Buffered() != 2 {
+		t.Errorf("Writer flushed early: dst has %v bytes, %v buffered", dst.Len(), w.Buffered())
+	}
+	if err := w.WriteInt24(2, BigEndian); err != nil {
+		t.Fatalf("Writer.WriteInt24() error = %v", err)
+	}
+	if dst.Len() != 5 || w.Buffered() != 0 {
+		t.Errorf("Writer didn't flush when full: dst has %v bytes, %v buffered", dst.Len(), w.Buffered())
+	}
+	if err := w.WriteString("hi"); err != nil {
+		t.Fatalf("Writer.WriteString() error = %v", err)
+	}
+	if err := w.WriteUInt8(0xFF); err != nil {
+		t.Fatalf("Writer.WriteUInt8() error = %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Writer.Flush() error = %v", err)
+	}
+	want := []byte{0x00, 0x01, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x02, 'h', 'i', 0xFF}
+	if !bytes.Equal(dst.Bytes(), want) {
+		t.Errorf("Writer wrote %v, want %v", dst.Bytes(), want)
+	}
+}
+
+func TestWriter_Flush(t *testing.T) {
+	errBroken := errors.New("broken pipe")
+	tests := []struct {
+		name    string
+		dst     *failingWriter
+		wantErr error
+	}{
+		{name: "fails", dst: &failingWriter{n: 0, err: errBroken}, wantErr: errBroken},
+		{name: "fails halfway", dst: &failingWriter{n: 2, err: errBroken}, wantErr: errBroken},
+		{name: "short write", dst: &failingWriter{n: 2, err: nil}, wantErr: io.ErrShortWrite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriterTo(tt.dst)
+			if err := w.WriteInt32(1, BigEndian); err != nil {
+				t.Fatalf("Writer.WriteInt32() error = %v", err)
+			}
+			if err := w.Flush(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Writer.Flush() error = %v, want %v", err, tt.wantErr)
+			}
+			if err := w.WriteInt24(1, BigEndian); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Writer.WriteInt24() after a failed flush error = %v, want %v", err, tt.wantErr)
+			}
+			if err := w.Flush(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Writer.Flush() again error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	w := NewWriter()
+	if err := w.WriteInt32(1, BigEndian); err != nil {
+		t.Fatalf("Writer.WriteInt32() error = %v", err)
+	}
+	if err := w.Flush(); err != nil || w.Buffer.Len() != 4 {
+		t.Errorf("Writer.Flush() on an in-memory Writer = %v with %v bytes, want nil with 4 bytes", err, w.Buffer.Len())
+	}
+}
+
+func TestWriter_WriteBoolean(t *testing.T) {
+	type fields struct {
+		Buffer *bytes.Buffer
+	}
+	type args struct {
+		data []bool
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "false", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: []bool{false}}, want: []byte{0x00}, wantErr: false},
+		{name: "true", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: []bool{true}}, want: []byte{0x01}, wantErr: false},
+		{name: "three packed", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: []bool{true, false, true}}, want: []byte{0x05}, wantErr: false},
+		{name: "eight packed", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: []bool{true, false, false, false, false, false, false, true}}, want: []byte{0x81}, wantErr: false},
+		{name: "nine", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: []bool{true, true, true, true, true, true, true, true, true}}, want: []byte{0xFF, 0x01}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Writer{
+				Buffer: tt.fields.Buffer,
+			}
+			for _, data := range tt.args.data {
+				if err := w.WriteBoolean(data); (err != nil) != tt.wantErr {
+					t.Errorf("Writer.WriteBoolean() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+			if !bytes.Equal(tt.fields.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteBoolean() wrote %v, want %v", tt.fields.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteBoolean_Reset(t *testing.T) {
+	dst := new(bytes.Buffer)
+	w := NewWriterToSize(dst, 1)
+	w.WriteBoolean(false)
+	w.WriteBoolean(true)
+	if dst.Len() != 0 {
+		t.Errorf("Writer flushed a half packed byte")
+	}
+	w.WriteInt8(0x7F)
+	w.WriteBoolean(true)
+	w.WriteBoolean(false)
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Writer.Flush() error = %v", err)
+	}
+	want := []byte{0x02, 0x7F, 0x01}
+	if !bytes.Equal(dst.Bytes(), want) {
+		t.Errorf("Writer wrote %v, want %v", dst.Bytes(), want)
+	}
+}
+
+func TestWriter_WriteRRSInt32(t *testing.T) {
+	type fields struct {
+		Buffer *bytes.Buffer
+	}
+	type args struct {
+		data int32
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "zero", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0}, want: []byte{0x00}, wantErr: false},
+		{name: "one", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 1}, want: []byte{0x01}, wantErr: false},
+		{name: "minus one", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -1}, want: []byte{0x40}, wantErr: false},
+		{name: "one byte max", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 63}, want: []byte{0x3F}, wantErr: false},
+		{name: "one byte max minus", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -64}, want: []byte{0x7F}, wantErr: false},
+		{name: "two byte min", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 64}, want: []byte{0x80, 0x01}, wantErr: false},
+		{name: "two byte min minus", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -65}, want: []byte{0xC0, 0x01}, wantErr: false},
+		{name: "two byte max", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 8191}, want: []byte{0xBF, 0x7F}, wantErr: false},
+		{name: "three byte min", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 8192}, want: []byte{0x80, 0x80, 0x01}, wantErr: false},
+		{name: "three byte min minus", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -8193}, want: []byte{0xC0, 0x80, 0x01}, wantErr: false},
+		{name: "300", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 300}, want: []byte{0xAC, 0x04}, wantErr: false},
+		{name: "minus 300", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -300}, want: []byte{0xEB, 0x04}, wantErr: false},
+		{name: "million", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 1000000}, want: []byte{0x80, 0x89, 0x7A}, wantErr: false},
+		{name: "max", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 2147483647}, want: []byte{0xBF, 0xFF, 0xFF, 0xFF, 0x0F}, wantErr: false},
+		{name: "max minus", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -2147483648}, want: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x0F}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Writer{
+				Buffer: tt.fields.Buffer,
+			}
+			if err := w.WriteRRSInt32(tt.args.data); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteRRSInt32() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(tt.fields.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteRRSInt32() wrote %v, want %v", tt.fields.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteRRSLong(t *testing.T) {
+	type fields struct {
+		Buffer *bytes.Buffer
+	}
+	type args struct {
+		data LogicLong
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "zero", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: LogicLong{}}, want: []byte{0x00, 0x00}, wantErr: false},
+		{name: "tag", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: LogicLong{High: 63, Low: 72003}}, want: []byte{0x3F, 0x83, 0xE5, 0x08}, wantErr: false},
+		{name: "negative", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: LogicLong{High: -1, Low: -1}}, want: []byte{0x40, 0x40}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Writer{
+				Buffer: tt.fields.Buffer,
+			}
+			if err := w.WriteRRSLong(tt.args.data); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteRRSLong() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(tt.fields.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteRRSLong() wrote %v, want %v", tt.fields.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteFloat32(t *testing.T) {
+	type fields struct {
+		Buffer *bytes.Buffer
+	}
+	type args struct {
+		data       float32
+		endianness Endianness
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "zero BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0, endianness: BigEndian}, want: []byte{0x00, 0x00, 0x00, 0x00}, wantErr: false},
+		{name: "minus zero BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: float32(math.Copysign(0, -1)), endianness: BigEndian}, want: []byte{0x80, 0x00, 0x00, 0x00}, wantErr: false},
+		{name: "one and a half BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 1.5, endianness: BigEndian}, want: []byte{0x3F, 0xC0, 0x00, 0x00}, wantErr: false},
+		{name: "minus two BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -2, endianness: BigEndian}, want: []byte{0xC0, 0x00, 0x00, 0x00}, wantErr: false},
+		{name: "infinity BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: float32(math.Inf(1)), endianness: BigEndian}, want: []byte{0x7F, 0x80, 0x00, 0x00}, wantErr: false},
+		{name: "nan payload BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: math.Float32frombits(0x7FC00001), endianness: BigEndian}, want: []byte{0x7F, 0xC0, 0x00, 0x01}, wantErr: false},
+
+		{name: "one and a half LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 1.5, endianness: LittleEndian}, want: []byte{0x00, 0x00, 0xC0, 0x3F}, wantErr: false},
+		{name: "minus two LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -2, endianness: LittleEndian}, want: []byte{0x00, 0x00, 0x00, 0xC0}, wantErr: false},
+		{name: "signalling nan LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: math.Float32frombits(0x7F800001), endianness: LittleEndian}, want: []byte{0x01, 0x00, 0x80, 0x7F}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Writer{
+				Buffer: tt.fields.Buffer,
+			}
+			if err := w.WriteFloat32(tt.args.data, tt.args.endianness); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteFloat32() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(tt.fields.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteFloat32() wrote %v, want %v", tt.fields.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteFloat64(t *testing.T) {
+	type fields struct {
+		Buffer *bytes.Buffer
+	}
+	type args struct {
+		data       float64
+		endianness Endianness
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "zero BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 0, endianness: BigEndian}, want: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, wantErr: false},
+		{name: "minus zero BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: math.Copysign(0, -1), endianness: BigEndian}, want: []byte{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, wantErr: false},
+		{name: "one and a half BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 1.5, endianness: BigEndian}, want: []byte{0x3F, 0xF8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, wantErr: false},
+		{name: "minus infinity BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: math.Inf(-1), endianness: BigEndian}, want: []byte{0xFF, 0xF0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, wantErr: false},
+		{name: "max BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: math.MaxFloat64, endianness: BigEndian}, want: []byte{0x7F, 0xEF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+		{name: "nan payload BE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: math.Float64frombits(0x7FF8000000000001), endianness: BigEndian}, want: []byte{0x7F, 0xF8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}, wantErr: false},
+
+		{name: "one and a half LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: 1.5, endianness: LittleEndian}, want: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF8, 0x3F}, wantErr: false},
+		{name: "minus two LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: -2, endianness: LittleEndian}, want: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xC0}, wantErr: false},
+		{name: "signalling nan LE", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: math.Float64frombits(0x7FF0000000000001), endianness: LittleEndian}, want: []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF0, 0x7F}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Writer{
+				Buffer: tt.fields.Buffer,
+			}
+			if err := w.WriteFloat64(tt.args.data, tt.args.endianness); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteFloat64() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(tt.fields.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteFloat64() wrote %v, want %v", tt.fields.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteFloat_RoundTrip(t *testing.T) {
+	// Every payload bit has to survive, quiet or signalling
+	nans32 := []uint32{0x7FC00000, 0x7FC00001, 0x7F800001, 0xFFBFFFFF, 0x7FFFFFFF}
+	nans64 := []uint64{0x7FF8000000000000, 0x7FF8000000000001, 0x7FF0000000000001, 0xFFF7FFFFFFFFFFFF, 0x7FFFFFFFFFFFFFFF}
+	for _, endianness := range []Endianness{BigEndian, LittleEndian} {
+		w := NewWriter()
+		for _, bits := range nans32 {
+			if err := w.WriteFloat32(math.Float32frombits(bits), endianness); err != nil {
+				t.Fatalf("Writer.WriteFloat32() error = %v", err)
+			}
+		}
+		for _, bits := range nans64 {
+			if err := w.WriteFloat64(math.Float64frombits(bits), endianness); err != nil {
+				t.Fatalf("Writer.WriteFloat64() error = %v", err)
+			}
+		}
+		r := NewReader(w.Buffer.Bytes())
+		for _, bits := range nans32 {
+			got, err := r.ReadFloat32(endianness)
+			if err != nil || math.Float32bits(got) != bits {
+				t.Errorf("Reader.ReadFloat32() = %#x, %v, want %#x", math.Float32bits(got), err, bits)
+			}
+		}
+		for _, bits := range nans64 {
+			got, err := r.ReadFloat64(endianness)
+			if err != nil || math.Float64bits(got) != bits {
+				t.Errorf("Reader.ReadFloat64() = %#x, %v, want %#x", math.Float64bits(got), err, bits)
+			}
+		}
+	}
+}
+
+func TestWriter_WriteNullableString(t *testing.T) {
+	empty, hi := "", "hi"
+	type fields struct {
+		Buffer *bytes.Buffer
+	}
+	type args struct {
+		data *string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "null", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: nil}, want: []byte{0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+		{name: "empty", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: &empty}, want: []byte{0x00, 0x00, 0x00, 0x00}, wantErr: false},
+		{name: "two", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: &hi}, want: []byte{0x00, 0x00, 0x00, 0x02, 'h', 'i'}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Writer{
+				Buffer: tt.fields.Buffer,
+			}
+			if err := w.WriteNullableString(tt.args.data); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteNullableString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(tt.fields.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteNullableString() wrote %v, want %v", tt.fields.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteNullableBytes(t *testing.T) {
+	type fields struct {
+		Buffer *bytes.Buffer
+	}
+	type args struct {
+		data []byte
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr bool
+	}{
+		{name: "null", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: nil}, want: []byte{0xFF, 0xFF, 0xFF, 0xFF}, wantErr: false},
+		{name: "empty", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: []byte{}}, want: []byte{0x00, 0x00, 0x00, 0x00}, wantErr: false},
+		{name: "two", fields: fields{Buffer: new(bytes.Buffer)}, args: args{data: []byte{0xAB, 0xCD}}, want: []byte{0x00, 0x00, 0x00, 0x02, 0xAB, 0xCD}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Writer{
+				Buffer: tt.fields.Buffer,
+			}
+			if err := w.WriteNullableBytes(tt.args.data); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteNullableBytes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(tt.fields.Buffer.Bytes(), tt.want) {
+				t.Errorf("Writer.WriteNullableBytes() wrote %v, want %v", tt.fields.Buffer.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_WriteNullableCompressedString(t *testing.T) {
+	for _, want := range []*string{nil, new(string), strPtr("hello there!")} {
+		w := NewWriter()
+		if err := w.WriteNullableCompressedString(want); err != nil {
+			t.Fatalf("Writer.WriteNullableCompressedString() error = %v", err)
+		}
+		if want == nil && !bytes.Equal(w.Buffer.Bytes(), []byte{0xFF, 0xFF, 0xFF, 0xFF}) {
+			t.Errorf("Writer.WriteNullableCompressedString() wrote %v, want -1", w.Buffer.Bytes())
+		}
+		got, err := NewReader(w.Buffer.Bytes()).ReadNullableCompressedString()
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Reader.ReadNullableCompressedString() = %v, %v, want %v", got, err, want)
+		}
+	}
+}